@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timestamped trace of what the app did (requests, errors, saves, imports), shown in the console panel
+
+// Oldest lines are dropped beyond this
+const maxActivityLines = 1000
+
+type activityLog struct {
+	mu    sync.Mutex
+	lines []string
+	// Called with the full log text after every change
+	onChange func(text string)
+}
+
+func (l *activityLog) add(format string, args ...interface{}) {
+	l.mu.Lock()
+	line := time.Now().Format("15:04:05.000") + "  " + fmt.Sprintf(format, args...)
+	l.lines = append(l.lines, line)
+	if len(l.lines) > maxActivityLines {
+		l.lines = l.lines[len(l.lines)-maxActivityLines:]
+	}
+	text := strings.Join(l.lines, "\n")
+	onChange := l.onChange
+	l.mu.Unlock()
+	if onChange != nil {
+		onChange(text)
+	}
+}
+
+func (l *activityLog) text() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+func (l *activityLog) clear() {
+	l.mu.Lock()
+	l.lines = nil
+	onChange := l.onChange
+	l.mu.Unlock()
+	if onChange != nil {
+		onChange("")
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// Simple response assertions, one per line:
+//
+//	$.status == "ok"
+//	$.items length > 0
+//	$.count >= 10
+//	jsonpath $.data.id exists
+//	status == 200
+//	time < 500
+//	body contains "ok"
+//
+// The left side is a JSONPath expression, optionally followed by "length".
+// The right side is a JSON literal (strings must be quoted).
+
+// assertionHelp describes the syntax for the Tests tab
+const assertionHelp = `One assertion per line; empty lines and lines starting with # are skipped.
+
+status == 200            response status code (==, !=, >, >=, <, <=)
+time < 500               response time in milliseconds
+body contains "ok"       raw body contains the quoted text
+$.data.id exists         JSONPath matches something
+$.status == "ok"         JSONPath value compared with a JSON literal
+$.items length > 0       length of an array, object or string
+
+A JSONPath may be written with a "jsonpath " prefix, e.g. jsonpath $.data.id exists.`
+
+var assertionOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+type assertionResult struct {
+	Assertion string
+	Passed    bool
+	Detail    string
+}
+
+// splitAssertion finds the comparison operator outside of quoted strings and brackets,
+// so a JSONPath filter like $.items[?(@.price > 10)] keeps its own operators
+func splitAssertion(expr string) (left, op, right string, ok bool) {
+	inQuote := false
+	depth := 0
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if c == '"' && (i == 0 || expr[i-1] != '\\') {
+			inQuote = !inQuote
+			continue
+		}
+		if inQuote {
+			continue
+		}
+		switch c {
+		case '[', '(':
+			depth++
+			continue
+		case ']', ')':
+			if depth > 0 {
+				depth--
+			}
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+		for _, candidate := range assertionOps {
+			if strings.HasPrefix(expr[i:], candidate) {
+				return strings.TrimSpace(expr[:i]), candidate, strings.TrimSpace(expr[i+len(candidate):]), true
+			}
+		}
+	}
+	return "", "", "", false
+}
+
+func valueLength(v interface{}) (int, error) {
+	switch t := v.(type) {
+	case []interface{}:
+		return len(t), nil
+	case map[string]interface{}:
+		return len(t), nil
+	case string:
+		return len(t), nil
+	}
+	return 0, fmt.Errorf("length of %T is undefined", v)
+}
+
+func compareValues(actual interface{}, op string, expected interface{}) (bool, error) {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(actual, expected), nil
+	case "!=":
+		return !reflect.DeepEqual(actual, expected), nil
+	}
+	a, aNum := actual.(float64)
+	e, eNum := expected.(float64)
+	if !aNum || !eNum {
+		return false, fmt.Errorf("'%s' needs numbers on both sides", op)
+	}
+	switch op {
+	case ">":
+		return a > e, nil
+	case ">=":
+		return a >= e, nil
+	case "<":
+		return a < e, nil
+	case "<=":
+		return a <= e, nil
+	}
+	return false, fmt.Errorf("unknown operator '%s'", op)
+}
+
+func evaluateAssertion(expr string, data interface{}) assertionResult {
+	result := assertionResult{Assertion: expr}
+	left, op, right, ok := splitAssertion(expr)
+	if !ok {
+		result.Detail = "no comparison operator (==, !=, >, >=, <, <=)"
+		return result
+	}
+	useLength := false
+	if strings.HasSuffix(left, " length") {
+		useLength = true
+		left = strings.TrimSpace(strings.TrimSuffix(left, " length"))
+	}
+	actual, err := jsonpath.Get(left, data)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	if useLength {
+		n, err := valueLength(actual)
+		if err != nil {
+			result.Detail = err.Error()
+			return result
+		}
+		actual = float64(n)
+	}
+	var expected interface{}
+	if err := json.Unmarshal([]byte(right), &expected); err != nil {
+		result.Detail = fmt.Sprintf("right side is not a JSON value: %s", right)
+		return result
+	}
+	passed, err := compareValues(actual, op, expected)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	result.Passed = passed
+	actualJSON, _ := json.Marshal(actual)
+	result.Detail = "actual: " + string(actualJSON)
+	return result
+}
+
+// evaluateResponseAssertion checks status, time and body assertions, which don't
+// need a JSON body; ok is false for any other kind of line
+func evaluateResponseAssertion(expr string, statusCode int, elapsed time.Duration, body []byte) (assertionResult, bool) {
+	result := assertionResult{Assertion: expr}
+	field, rest, _ := strings.Cut(expr, " ")
+	rest = strings.TrimSpace(rest)
+	switch field {
+	case "status", "time":
+		actual := float64(statusCode)
+		if field == "time" {
+			actual = float64(elapsed.Milliseconds())
+		}
+		_, op, right, ok := splitAssertion(expr)
+		if !ok {
+			result.Detail = "no comparison operator (==, !=, >, >=, <, <=)"
+			return result, true
+		}
+		expected, err := strconv.ParseFloat(right, 64)
+		if err != nil {
+			result.Detail = fmt.Sprintf("right side is not a number: %s", right)
+			return result, true
+		}
+		passed, err := compareValues(actual, op, expected)
+		if err != nil {
+			result.Detail = err.Error()
+			return result, true
+		}
+		result.Passed = passed
+		result.Detail = fmt.Sprintf("actual: %g", actual)
+		if field == "time" {
+			result.Detail += " ms"
+		}
+		return result, true
+	case "body":
+		text, ok := strings.CutPrefix(rest, "contains ")
+		if !ok {
+			result.Detail = `expected body contains "text"`
+			return result, true
+		}
+		var needle string
+		if err := json.Unmarshal([]byte(strings.TrimSpace(text)), &needle); err != nil {
+			result.Detail = "the text to look for must be a quoted string"
+			return result, true
+		}
+		result.Passed = strings.Contains(string(body), needle)
+		if !result.Passed {
+			result.Detail = "not found in the body"
+		} else {
+			result.Detail = "found"
+		}
+		return result, true
+	}
+	return result, false
+}
+
+// evaluateExists checks that a JSONPath matches something
+func evaluateExists(expr, path string, data interface{}) assertionResult {
+	result := assertionResult{Assertion: expr}
+	value, err := jsonpath.Get(path, data)
+	if err != nil {
+		result.Detail = "no match"
+		return result
+	}
+	if values, ok := value.([]interface{}); ok && len(values) == 0 && strings.ContainsAny(path, "*[?") {
+		// Wildcards and filters return a list, which is empty when nothing matched
+		result.Detail = "no match"
+		return result
+	}
+	result.Passed = true
+	valueJSON, _ := json.Marshal(value)
+	result.Detail = "actual: " + string(valueJSON)
+	return result
+}
+
+// evaluateAssertions runs every assertion line against a response
+func evaluateAssertions(lines []string, statusCode int, elapsed time.Duration, body []byte) []assertionResult {
+	var data interface{}
+	bodyErr := json.Unmarshal(body, &data)
+	results := []assertionResult{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if res, ok := evaluateResponseAssertion(line, statusCode, elapsed, body); ok {
+			results = append(results, res)
+			continue
+		}
+		if bodyErr != nil {
+			results = append(results, assertionResult{Assertion: line, Detail: "response is not valid JSON"})
+			continue
+		}
+		expr := strings.TrimSpace(strings.TrimPrefix(line, "jsonpath "))
+		if path, ok := strings.CutSuffix(expr, " exists"); ok {
+			results = append(results, evaluateExists(line, strings.TrimSpace(path), data))
+			continue
+		}
+		res := evaluateAssertion(expr, data)
+		res.Assertion = line
+		results = append(results, res)
+	}
+	return results
+}
+
+// assertionsPassed reports whether every assertion passed
+func assertionsPassed(results []assertionResult) bool {
+	for _, res := range results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// Request authentication, applied as an Authorization header when the request is built
+
+type RequestAuth struct {
+	Type  string `json:"type"`
+	Token string `json:"token,omitempty"`
+	// Basic auth credentials
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// Auth types; none (the default) sends no Authorization header
+const (
+	authNone   = ""
+	authBearer = "bearer"
+	authBasic  = "basic"
+)
+
+// Auth types in the order they are offered, with their labels
+var authTypes = []struct{ Type, Label string }{
+	{authNone, "No Auth"},
+	{authBearer, "Bearer Token"},
+	{authBasic, "Basic Auth"},
+}
+
+func authTypeLabels() []string {
+	labels := []string{}
+	for _, t := range authTypes {
+		labels = append(labels, t.Label)
+	}
+	return labels
+}
+
+func authTypeLabel(typ string) string {
+	for _, t := range authTypes {
+		if t.Type == typ {
+			return t.Label
+		}
+	}
+	return authTypes[0].Label
+}
+
+func authTypeFromLabel(label string) string {
+	for _, t := range authTypes {
+		if t.Label == label {
+			return t.Type
+		}
+	}
+	return authNone
+}
+
+// hasAuth reports whether the request carries authentication of its own
+func hasAuth(r APIRequest) bool {
+	return r.Auth != nil && r.Auth.Type != authNone
+}
+
+// applyAuth sets the Authorization header for the request's auth.
+// A header typed in the headers box takes precedence and is left alone.
+func applyAuth(req *http.Request, auth *RequestAuth) {
+	if auth == nil || req.Header.Get("Authorization") != "" {
+		return
+	}
+	switch auth.Type {
+	case authBearer:
+		if auth.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+		}
+	case authBasic:
+		if auth.Username != "" || auth.Password != "" {
+			req.SetBasicAuth(auth.Username, auth.Password)
+		}
+	}
+}
+
+// authFromHeader turns an Authorization header value into auth settings.
+// Basic credentials are split at the first colon, so a password may contain colons.
+func authFromHeader(value string) (*RequestAuth, bool) {
+	scheme, credentials, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok {
+		return nil, false
+	}
+	credentials = strings.TrimSpace(credentials)
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return &RequestAuth{Type: authBearer, Token: credentials}, true
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return nil, false
+		}
+		username, password, _ := strings.Cut(string(decoded), ":")
+		return &RequestAuth{Type: authBasic, Username: username, Password: password}, true
+	}
+	return nil, false
+}
+
+// Auth in Postman collection v2.1 format: {"type": "basic", "basic": [{"key": "username", "value": "..."}]}
+type postmanKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+}
+
+func (p *postmanAuth) toAuth() *RequestAuth {
+	if p == nil {
+		return nil
+	}
+	value := func(params []postmanKeyValue, key string) string {
+		for _, kv := range params {
+			if kv.Key == key {
+				return kv.Value
+			}
+		}
+		return ""
+	}
+	switch p.Type {
+	case "bearer":
+		return &RequestAuth{Type: authBearer, Token: value(p.Bearer, "token")}
+	case "basic":
+		return &RequestAuth{Type: authBasic, Username: value(p.Basic, "username"), Password: value(p.Basic, "password")}
+	}
+	return nil
+}
+
+func toPostmanAuth(auth *RequestAuth) *postmanAuth {
+	if auth == nil {
+		return nil
+	}
+	switch auth.Type {
+	case authBearer:
+		return &postmanAuth{Type: "bearer", Bearer: []postmanKeyValue{{Key: "token", Value: auth.Token}}}
+	case authBasic:
+		return &postmanAuth{Type: "basic", Basic: []postmanKeyValue{
+			{Key: "username", Value: auth.Username},
+			{Key: "password", Value: auth.Password},
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Conditional requests: validators and body of the last successful response per
+// method and URL, kept for the session so a 304 Not Modified can show the cached body
+
+type cachedResponse struct {
+	ETag         string
+	LastModified string
+	ContentType  string
+	Body         []byte
+	Stored       time.Time
+}
+
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: map[string]cachedResponse{}}
+}
+
+func cacheKey(r APIRequest) string {
+	return r.Method + " " + r.URL
+}
+
+func (c *responseCache) get(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// store remembers a response if it carries an ETag or Last-Modified validator
+func (c *responseCache) store(key string, header http.Header, body []byte) {
+	etag, lastModified := header.Get("ETag"), header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cachedResponse{
+		ETag:         etag,
+		LastModified: lastModified,
+		ContentType:  header.Get("Content-Type"),
+		Body:         body,
+		Stored:       time.Now(),
+	}
+}
+
+// applyConditionalHeaders sends the cached validators, unless the request sets them itself
+func applyConditionalHeaders(req *http.Request, cached cachedResponse) {
+	if cached.ETag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+}
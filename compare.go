@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Side-by-side comparison of two saved requests
+
+// A named part of a request, split into lines for diffing
+type requestField struct {
+	Label string
+	Lines []string
+}
+
+func requestFields(r APIRequest) []requestField {
+	headers := make([]string, 0, len(r.Headers))
+	for k, v := range r.Headers {
+		for _, value := range headerValues(v) {
+			headers = append(headers, k+": "+value)
+		}
+	}
+	sort.Strings(headers)
+	headers = append(headers, r.CommentedHeaders...)
+
+	// Indent JSON bodies the same way so formatting alone doesn't show as a difference
+	body := r.Body
+	var pretty bytes.Buffer
+	if json.Valid([]byte(body)) && json.Indent(&pretty, []byte(body), "", "    ") == nil {
+		body = pretty.String()
+	}
+	bodyLines := []string{}
+	if r.BodyMode == bodyModeFile {
+		bodyLines = append(bodyLines, "File: "+r.BodyFile, fmt.Sprintf("Chunked: %t", r.ChunkedUpload))
+	} else if r.BodyMode == bodyModeMultipart || r.BodyMode == bodyModeURLEncoded {
+		bodyLines = formFieldLines(r.FormFields)
+	} else if body != "" {
+		bodyLines = strings.Split(body, "\n")
+	}
+
+	settings := []string{
+		fmt.Sprintf("Timeout: %ds", r.TimeoutSeconds),
+		fmt.Sprintf("Retries: %d", r.Retries),
+		"Expected status: " + formatStatusCodes(r.ExpectedStatus),
+		fmt.Sprintf("Follow redirects: %t", r.FollowRedirects),
+		fmt.Sprintf("Skip TLS verification: %t", r.InsecureSkipVerify),
+		fmt.Sprintf("Gzip body: %t", r.GzipBody),
+		fmt.Sprintf("Conditional requests: %t", r.ConditionalRequests),
+	}
+	if r.Host != "" {
+		settings = append(settings, "Host: "+r.Host)
+	}
+	if hasAuth(r) {
+		settings = append(settings, "Auth: "+authTypeLabel(r.Auth.Type))
+		if r.Auth.Type == authBasic {
+			settings = append(settings, "Auth username: "+r.Auth.Username)
+		}
+	}
+	if r.Proxy != "" {
+		settings = append(settings, "Proxy: "+r.Proxy)
+	}
+	if r.AcceptLanguage != "" {
+		settings = append(settings, "Language: "+r.AcceptLanguage)
+	}
+	if r.EnvironmentOverride != "" {
+		settings = append(settings, "Environment: "+r.EnvironmentOverride)
+	}
+
+	return []requestField{
+		{Label: "Method", Lines: []string{r.Method}},
+		{Label: "URL", Lines: []string{r.URL}},
+		{Label: "Headers", Lines: headers},
+		{Label: "Body", Lines: bodyLines},
+		{Label: "Settings", Lines: settings},
+	}
+}
+
+// diffLines marks the lines of a and b that are not part of their longest common subsequence
+func diffLines(a, b []string) (aChanged, bChanged []bool) {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	aChanged = make([]bool, len(a))
+	bChanged = make([]bool, len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			aChanged[i] = true
+			i++
+		default:
+			bChanged[j] = true
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		aChanged[i] = true
+	}
+	for ; j < len(b); j++ {
+		bChanged[j] = true
+	}
+	return aChanged, bChanged
+}
+
+// One variable across two environments being compared
+type variableDiff struct {
+	Name    string
+	Left    string
+	Right   string
+	InLeft  bool
+	InRight bool
+}
+
+func (d variableDiff) differs() bool {
+	return d.InLeft != d.InRight || d.Left != d.Right
+}
+
+// diffVariables lists every variable defined in either environment, sorted by name
+func diffVariables(left, right map[string]string) []variableDiff {
+	names := []string{}
+	for k := range left {
+		names = append(names, k)
+	}
+	for k := range right {
+		if _, ok := left[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	diffs := make([]variableDiff, 0, len(names))
+	for _, name := range names {
+		l, inLeft := left[name]
+		r, inRight := right[name]
+		diffs = append(diffs, variableDiff{Name: name, Left: l, Right: r, InLeft: inLeft, InRight: inRight})
+	}
+	return diffs
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cookies set by responses, sent back on later requests to the same site. Each
+// workspace has its own jar. The standard library jar can't list or delete its
+// cookies, so this one keeps them in a plain list; domain and path matching follow
+// RFC 6265, without the public suffix list.
+
+type storedCookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires"` // Zero for a session cookie
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"httpOnly,omitempty"`
+	// Sent to Domain only, not its subdomains, because the response set no Domain attribute
+	HostOnly bool `json:"hostOnly,omitempty"`
+}
+
+func (c storedCookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+func (c storedCookie) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if c.HostOnly {
+		if host != c.Domain {
+			return false
+		}
+	} else if !domainMatches(host, c.Domain) {
+		return false
+	}
+	if c.Secure && u.Scheme != "https" {
+		return false
+	}
+	return pathMatches(requestPath(u), c.Path)
+}
+
+func domainMatches(host, domain string) bool {
+	if host == domain {
+		return true
+	}
+	// IP addresses only match themselves
+	return strings.HasSuffix(host, "."+domain) && net.ParseIP(host) == nil
+}
+
+func pathMatches(requestPath, cookiePath string) bool {
+	if requestPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+func requestPath(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// defaultCookiePath is the directory of the request path, used when a cookie sets no Path
+func defaultCookiePath(u *url.URL) string {
+	p := requestPath(u)
+	if !strings.HasPrefix(p, "/") || strings.Count(p, "/") == 1 {
+		return "/"
+	}
+	return path.Dir(p)
+}
+
+type cookieJar struct {
+	mu      sync.Mutex
+	cookies []storedCookie
+	// Called after a response changed the stored cookies
+	onChange func()
+}
+
+// SetCookies stores the cookies of a response from u, replacing any with the same
+// name, domain and path. A cookie that is already expired deletes the stored one.
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	now := time.Now()
+	host := strings.ToLower(u.Hostname())
+	changed := false
+	j.mu.Lock()
+	for _, c := range cookies {
+		sc := storedCookie{Name: c.Name, Value: c.Value, Path: c.Path, Secure: c.Secure, HttpOnly: c.HttpOnly}
+		if sc.Path == "" || !strings.HasPrefix(sc.Path, "/") {
+			sc.Path = defaultCookiePath(u)
+		}
+		domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		switch {
+		case domain == "":
+			sc.Domain, sc.HostOnly = host, true
+		case domainMatches(host, domain):
+			sc.Domain = domain
+		default:
+			// A site can't set cookies for another domain
+			continue
+		}
+		switch {
+		case c.MaxAge < 0:
+			sc.Expires = now.Add(-time.Second)
+		case c.MaxAge > 0:
+			sc.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			sc.Expires = c.Expires
+		}
+		kept := j.cookies[:0]
+		for _, old := range j.cookies {
+			if old.Name != sc.Name || old.Domain != sc.Domain || old.Path != sc.Path {
+				kept = append(kept, old)
+			}
+		}
+		j.cookies = kept
+		if !sc.expired(now) {
+			j.cookies = append(j.cookies, sc)
+		}
+		changed = true
+	}
+	onChange := j.onChange
+	j.mu.Unlock()
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
+// Cookies returns the cookies to send to u, those with longer paths first
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	matching := []storedCookie{}
+	for _, c := range j.cookies {
+		if !c.expired(now) && c.matches(u) {
+			matching = append(matching, c)
+		}
+	}
+	sort.SliceStable(matching, func(a, b int) bool { return len(matching[a].Path) > len(matching[b].Path) })
+	cookies := []*http.Cookie{}
+	for _, c := range matching {
+		cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+// list returns the stored cookies that haven't expired, by domain, path and name
+func (j *cookieJar) list() []storedCookie {
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	cookies := []storedCookie{}
+	for _, c := range j.cookies {
+		if !c.expired(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	sort.Slice(cookies, func(a, b int) bool {
+		if cookies[a].Domain != cookies[b].Domain {
+			return cookies[a].Domain < cookies[b].Domain
+		}
+		if cookies[a].Path != cookies[b].Path {
+			return cookies[a].Path < cookies[b].Path
+		}
+		return cookies[a].Name < cookies[b].Name
+	})
+	return cookies
+}
+
+// remove deletes the cookie with c's name, domain and path
+func (j *cookieJar) remove(c storedCookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	kept := j.cookies[:0]
+	for _, old := range j.cookies {
+		if old.Name != c.Name || old.Domain != c.Domain || old.Path != c.Path {
+			kept = append(kept, old)
+		}
+	}
+	j.cookies = kept
+}
+
+func (j *cookieJar) clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cookies = nil
+}
+
+func getCookiesPath() string {
+	dir, _ := os.UserHomeDir()
+	return filepath.Join(dir, ".postman-go-cookies.json")
+}
+
+// cookieJars holds a jar per workspace. When persisting, the jars are written to
+// disk after every change so sessions survive a restart.
+type cookieJars struct {
+	mu      sync.Mutex
+	jars    map[string]*cookieJar
+	persist bool
+}
+
+// loadCookieJars reads the saved jars when persist is on; an unreadable file starts them empty
+func loadCookieJars(persist bool) *cookieJars {
+	jars := &cookieJars{jars: map[string]*cookieJar{}, persist: persist}
+	if !persist {
+		return jars
+	}
+	data, err := os.ReadFile(getCookiesPath())
+	if err != nil {
+		return jars
+	}
+	saved := map[string][]storedCookie{}
+	if json.Unmarshal(data, &saved) != nil {
+		return jars
+	}
+	for workspace, cookies := range saved {
+		jars.forWorkspace(workspace).cookies = cookies
+	}
+	return jars
+}
+
+func (j *cookieJars) forWorkspace(workspace string) *cookieJar {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	jar, ok := j.jars[workspace]
+	if !ok {
+		jar = &cookieJar{onChange: func() { _ = j.save() }}
+		j.jars[workspace] = jar
+	}
+	return jar
+}
+
+// setPersist turns saving on or off; turning it off deletes the saved file
+func (j *cookieJars) setPersist(persist bool) error {
+	j.mu.Lock()
+	j.persist = persist
+	j.mu.Unlock()
+	if !persist {
+		if err := os.Remove(getCookiesPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return j.save()
+}
+
+func (j *cookieJars) save() error {
+	j.mu.Lock()
+	if !j.persist {
+		j.mu.Unlock()
+		return nil
+	}
+	saved := map[string][]storedCookie{}
+	for workspace, jar := range j.jars {
+		if cookies := jar.list(); len(cookies) > 0 {
+			saved[workspace] = cookies
+		}
+	}
+	j.mu.Unlock()
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getCookiesPath(), data, 0600)
+}
+
+// rename moves a workspace's jar to its new name
+func (j *cookieJars) rename(from, to string) error {
+	j.mu.Lock()
+	if jar, ok := j.jars[from]; ok {
+		delete(j.jars, from)
+		j.jars[to] = jar
+	}
+	j.mu.Unlock()
+	return j.save()
+}
+
+// remove drops the jar of a deleted workspace
+func (j *cookieJars) remove(workspace string) error {
+	j.mu.Lock()
+	delete(j.jars, workspace)
+	j.mu.Unlock()
+	return j.save()
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Import of curl command lines, as given in API docs or by "Copy as cURL" in browser devtools:
+//
+//	curl -X POST 'https://example.com/api/users' \
+//	  -H 'Content-Type: application/json' \
+//	  --data-raw '{"name": "test"}'
+
+// splitShellWords splits a command line the way a POSIX shell would: single quotes
+// are literal, double quotes allow \" \\ \$ and \` escapes, $'...' strings take C
+// escapes, and a backslash at the end of a line continues the command.
+func splitShellWords(cmd string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\':
+			if i+1 < len(cmd) && cmd[i+1] == '\r' {
+				i++
+			}
+			if i+1 >= len(cmd) {
+				continue
+			}
+			i++
+			if cmd[i] != '\n' {
+				word.WriteByte(cmd[i])
+				inWord = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(cmd[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated single quote starting at offset %d", i)
+			}
+			word.WriteString(cmd[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case c == '$' && i+1 < len(cmd) && cmd[i+1] == '\'':
+			s, next, err := readANSICString(cmd, i+1)
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(s)
+			inWord = true
+			i = next - 1
+		case c == '"':
+			closed := false
+			for i++; i < len(cmd); i++ {
+				if cmd[i] == '"' {
+					closed = true
+					break
+				}
+				if cmd[i] == '\\' && i+1 < len(cmd) && strings.IndexByte("\"\\$`\n", cmd[i+1]) != -1 {
+					i++
+					if cmd[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(cmd[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// readANSICString reads a $'...' string whose opening quote is at src[start]
+// and returns its value and the index after the closing quote
+func readANSICString(src string, start int) (string, int, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		c := src[i]
+		if c == '\'' {
+			return sb.String(), i + 1, nil
+		}
+		if c != '\\' || i+1 >= len(src) {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		switch src[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u', 'x':
+			digits := 4
+			if src[i] == 'x' {
+				digits = 2
+			}
+			if i+digits < len(src) {
+				if r, err := strconv.ParseUint(src[i+1:i+1+digits], 16, 32); err == nil {
+					sb.WriteRune(rune(r))
+					i += digits
+					continue
+				}
+			}
+			sb.WriteByte(src[i])
+		default:
+			sb.WriteByte(src[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated $' string starting at offset %d", start-1)
+}
+
+// curl options that take no value and don't change the request
+var curlIgnoredFlags = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true, "-v": true, "--verbose": true,
+	"-i": true, "--include": true, "--compressed": true, "-L": true, "--location": true,
+	"-f": true, "--fail": true, "-N": true, "--no-buffer": true, "-#": true, "--progress-bar": true,
+}
+
+// curl options that take a value; the ones not handled by parseCurlCommand
+// (output files, retries...) don't change the request and are skipped
+var curlOptions = map[string]bool{
+	"-X": true, "--request": true, "--url": true, "-H": true, "--header": true,
+	"-d": true, "--data": true, "--data-ascii": true, "--data-binary": true, "--data-raw": true, "--json": true,
+	"-u": true, "--user": true, "-A": true, "--user-agent": true, "-e": true, "--referer": true,
+	"-b": true, "--cookie": true, "-x": true, "--proxy": true, "-m": true, "--max-time": true,
+	"-o": true, "--output": true, "-w": true, "--write-out": true, "--connect-timeout": true,
+	"--retry": true, "-c": true, "--cookie-jar": true, "-F": true, "--form": true,
+}
+
+// Short options taking a value, which may be attached to them (-XPOST)
+var curlValueOptions = map[string]bool{
+	"-X": true, "-H": true, "-d": true, "-u": true, "-A": true, "-e": true, "-b": true,
+	"-x": true, "-m": true, "-o": true, "-w": true, "-c": true, "-F": true,
+}
+
+// parseCurlCommand builds a request from a curl command line. Without -X, a body
+// makes it a POST as it does in curl, and -d data defaults to a form content type.
+func parseCurlCommand(cmd string) (APIRequest, error) {
+	words, err := splitShellWords(strings.TrimSpace(cmd))
+	if err != nil {
+		return APIRequest{}, err
+	}
+	if len(words) > 0 && words[0] == "$" {
+		words = words[1:]
+	}
+	if len(words) == 0 || words[0] != "curl" {
+		return APIRequest{}, fmt.Errorf("the command must start with curl")
+	}
+
+	req := newAPIRequest("", "")
+	method, url := "", ""
+	data := []string{}
+	formData := false
+	for i := 1; i < len(words); i++ {
+		opt := words[i]
+		if !strings.HasPrefix(opt, "-") || opt == "-" {
+			if url != "" {
+				return APIRequest{}, fmt.Errorf("more than one URL given (%s and %s)", url, opt)
+			}
+			url = opt
+			continue
+		}
+		if len(opt) > 2 && opt[1] != '-' {
+			rest := []string{}
+			if curlValueOptions[opt[:2]] {
+				// A short option may carry its value directly, as in -XPOST or -H'Accept: */*'
+				rest = []string{opt[:2], opt[2:]}
+			} else {
+				// Flags may be bundled, as in -sSL
+				for _, flag := range opt[1:] {
+					rest = append(rest, "-"+string(flag))
+				}
+			}
+			words = append(words[:i], append(rest, words[i+1:]...)...)
+			opt = words[i]
+		}
+		switch {
+		case curlIgnoredFlags[opt]:
+			continue
+		case opt == "-k" || opt == "--insecure":
+			req.InsecureSkipVerify = true
+			continue
+		case opt == "-I" || opt == "--head":
+			method = "HEAD"
+			continue
+		case !curlOptions[opt]:
+			return APIRequest{}, fmt.Errorf("unsupported option %s", opt)
+		case i+1 >= len(words):
+			return APIRequest{}, fmt.Errorf("%s needs a value", opt)
+		}
+		i++
+		v := words[i]
+		switch opt {
+		case "-X", "--request":
+			method = strings.ToUpper(v)
+		case "--url":
+			url = v
+		case "-H", "--header":
+			name, headerValue, ok := strings.Cut(v, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return APIRequest{}, fmt.Errorf("header %q is not in Name: value form", v)
+			}
+			name = strings.TrimSpace(name)
+			if prev, ok := req.Headers[name]; ok {
+				req.Headers[name] = joinHeaderValues([]string{prev, strings.TrimSpace(headerValue)})
+			} else {
+				req.Headers[name] = strings.TrimSpace(headerValue)
+			}
+		case "-d", "--data", "--data-ascii", "--data-binary":
+			if strings.HasPrefix(v, "@") {
+				req.BodyMode, req.BodyFile = bodyModeFile, v[1:]
+			} else {
+				data = append(data, v)
+			}
+			formData = true
+		case "--data-raw":
+			data = append(data, v)
+			formData = true
+		case "-F", "--form":
+			key, value, ok := strings.Cut(v, "=")
+			if !ok {
+				return APIRequest{}, fmt.Errorf("form field %q is not in name=value form", v)
+			}
+			field := FormField{Key: key, Value: value}
+			if strings.HasPrefix(value, "@") {
+				// curl allows ;type= and ;filename= after the path
+				path, options, _ := strings.Cut(value[1:], ";")
+				field.Value, field.IsFile = path, true
+				for _, option := range strings.Split(options, ";") {
+					if t, ok := strings.CutPrefix(strings.TrimSpace(option), "type="); ok {
+						field.ContentType = t
+					}
+				}
+			} else if text, t, ok := strings.Cut(value, ";type="); ok {
+				field.Value, field.ContentType = text, t
+			}
+			req.BodyMode = bodyModeMultipart
+			req.FormFields = append(req.FormFields, field)
+		case "--json":
+			data = append(data, v)
+			req.Headers["Content-Type"] = "application/json"
+			req.Headers["Accept"] = "application/json"
+		case "-u", "--user":
+			username, password, _ := strings.Cut(v, ":")
+			req.Auth = &RequestAuth{Type: authBasic, Username: username, Password: password}
+		case "-A", "--user-agent":
+			req.Headers["User-Agent"] = v
+		case "-e", "--referer":
+			req.Headers["Referer"] = v
+		case "-b", "--cookie":
+			req.Headers["Cookie"] = v
+		case "-x", "--proxy":
+			req.Proxy = v
+		case "-m", "--max-time":
+			seconds, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return APIRequest{}, fmt.Errorf("%s value %q is not a number of seconds", opt, v)
+			}
+			req.TimeoutSeconds = int(seconds + 0.999)
+		}
+	}
+	if url == "" {
+		return APIRequest{}, fmt.Errorf("no URL found")
+	}
+	if method == "" {
+		method = "GET"
+		if len(data) > 0 || req.BodyMode != bodyModeRaw {
+			method = "POST"
+		}
+	}
+	req.Method, req.URL, req.Name = method, url, url
+	// curl joins repeated -d values with & as form fields
+	req.Body = strings.Join(data, "&")
+	if formData && !hasContentType(req.Headers) {
+		req.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	return req, nil
+}
+
+func hasContentType(headers map[string]string) bool {
+	for name := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Import of .env files into an environment:
+//
+//	# comment
+//	export API_URL=https://example.com   # trailing comment
+//	TOKEN='literal $value'
+//	GREETING="Hello\nWorld"
+
+var dotEnvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// parseDotEnv reads KEY=VALUE lines. Single-quoted values are literal, double-quoted
+// ones take \n, \t, \" and \\ escapes and may span lines, and unquoted values end at
+// a " #" comment. Lines that can't be read are reported with their line number and skipped.
+func parseDotEnv(text string) (map[string]string, []string) {
+	vars := map[string]string{}
+	problems := []string{}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: expected KEY=VALUE", lineNo))
+			continue
+		}
+		if !dotEnvKeyPattern.MatchString(key) {
+			problems = append(problems, fmt.Sprintf("line %d: %q is not a valid variable name", lineNo, key))
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end == -1 {
+				problems = append(problems, fmt.Sprintf("line %d: unterminated single quote", lineNo))
+				continue
+			}
+			value = value[1 : end+1]
+		case strings.HasPrefix(value, `"`):
+			// The closing quote may be on a later line
+			raw := value[1:]
+			var sb strings.Builder
+			closed := false
+			for !closed {
+				for j := 0; j < len(raw); j++ {
+					if raw[j] == '\\' && j+1 < len(raw) {
+						j++
+						switch raw[j] {
+						case 'n':
+							sb.WriteByte('\n')
+						case 't':
+							sb.WriteByte('\t')
+						case 'r':
+							sb.WriteByte('\r')
+						default:
+							sb.WriteByte(raw[j])
+						}
+						continue
+					}
+					if raw[j] == '"' {
+						closed = true
+						break
+					}
+					sb.WriteByte(raw[j])
+				}
+				if closed || i+1 >= len(lines) {
+					break
+				}
+				i++
+				sb.WriteByte('\n')
+				raw = lines[i]
+			}
+			if !closed {
+				problems = append(problems, fmt.Sprintf("line %d: unterminated double quote", lineNo))
+				continue
+			}
+			value = sb.String()
+		default:
+			if j := strings.Index(value, " #"); j != -1 {
+				value = strings.TrimSpace(value[:j])
+			}
+		}
+		vars[key] = value
+	}
+	return vars, problems
+}
+
+// dotEnvEnvironmentName names the environment imported from a file: ".env.staging"
+// becomes "staging", and a plain ".env" keeps its file name
+func dotEnvEnvironmentName(fileName string) string {
+	name := strings.TrimPrefix(path.Base(fileName), ".env.")
+	if name == "" || name == ".env" {
+		return ".env"
+	}
+	return name
+}
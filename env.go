@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Environment variables and {{var}} substitution
+
+type Environment struct {
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+const noEnvironment = "No Environment"
+
+// Option in a request's environment override meaning "use the selected environment"
+const activeEnvironmentOption = "(Active environment)"
+
+// A reference may carry a type, {{count:int}}, so JSON bodies get a literal instead of a string
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)(?::(int|number|bool|json))?\s*\}\}`)
+
+// A typed reference that is a whole JSON string value, quotes included
+var quotedTypedVariablePattern = regexp.MustCompile(`"\{\{\s*([A-Za-z0-9_.\-]+):(int|number|bool|json)\s*\}\}"`)
+
+// Variable scopes. A precedence order lists them from highest to lowest priority.
+const (
+	scopeEnvironment = "Environment"
+	scopeCollection  = "Collection"
+	scopeGlobal      = "Global"
+)
+
+var defaultVariablePrecedence = []string{scopeEnvironment, scopeCollection, scopeGlobal}
+
+const precedenceSeparator = " > "
+
+// variablePrecedenceOptions lists every ordering of the scopes, e.g. "Environment > Collection > Global"
+func variablePrecedenceOptions() []string {
+	var options []string
+	var permute func(prefix, rest []string)
+	permute = func(prefix, rest []string) {
+		if len(rest) == 0 {
+			options = append(options, strings.Join(prefix, precedenceSeparator))
+			return
+		}
+		for i := range rest {
+			remaining := append(append([]string{}, rest[:i]...), rest[i+1:]...)
+			permute(append(append([]string{}, prefix...), rest[i]), remaining)
+		}
+	}
+	permute(nil, defaultVariablePrecedence)
+	return options
+}
+
+// parseVariablePrecedence reads an option from variablePrecedenceOptions,
+// falling back to the default order for anything else
+func parseVariablePrecedence(option string) []string {
+	for _, o := range variablePrecedenceOptions() {
+		if o == option {
+			return strings.Split(option, precedenceSeparator)
+		}
+	}
+	return defaultVariablePrecedence
+}
+
+// resolveVariables merges variable scopes into one map. When a name is defined in
+// several scopes, the scope listed first in order wins.
+func resolveVariables(order []string, scopes map[string]map[string]string) map[string]string {
+	vars := map[string]string{}
+	for i := len(order) - 1; i >= 0; i-- {
+		for k, v := range scopes[order[i]] {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// Max depth for variables that reference other variables
+const maxVariableDepth = 10
+
+// substituteVariables replaces {{name}} references with their values.
+// Values may reference other variables; unknown names are left as-is.
+func substituteVariables(text string, vars map[string]string) string {
+	return substituteDepth(text, vars, 0)
+}
+
+func substituteDepth(text string, vars map[string]string, depth int) string {
+	if depth >= maxVariableDepth {
+		return text
+	}
+	return variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			return match
+		}
+		return substituteDepth(value, vars, depth+1)
+	})
+}
+
+// typedLiteral converts a variable value to a JSON literal of the given type
+func typedLiteral(value, typ string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch typ {
+	case "int":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an int", value)
+		}
+		return strconv.FormatInt(n, 10), nil
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil || !json.Valid([]byte(value)) {
+			return "", fmt.Errorf("%q is not a number", value)
+		}
+		return value, nil
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a bool", value)
+		}
+		return strconv.FormatBool(b), nil
+	case "json":
+		if !json.Valid([]byte(value)) {
+			return "", fmt.Errorf("%q is not valid JSON", value)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown type %q", typ)
+}
+
+// substituteJSONBody substitutes variables in a JSON body. A string value that is
+// exactly a typed reference, "{{count:int}}", is replaced by the bare literal so
+// numbers and booleans keep their type; everything else is plain substitution.
+func substituteJSONBody(text string, vars map[string]string) string {
+	if !json.Valid([]byte(text)) {
+		return substituteVariables(text, vars)
+	}
+	text = quotedTypedVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		sub := quotedTypedVariablePattern.FindStringSubmatch(match)
+		value, ok := vars[sub[1]]
+		if !ok {
+			return match
+		}
+		literal, err := typedLiteral(substituteVariables(value, vars), sub[2])
+		if err != nil {
+			return match
+		}
+		return literal
+	})
+	return substituteVariables(text, vars)
+}
+
+func referencedVariables(text string) []string {
+	names := []string{}
+	for _, m := range variablePattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// lintVariables checks every {{var}} used in texts and reports undefined,
+// empty and self-referential variables. The result is sorted and deduplicated.
+func lintVariables(vars map[string]string, texts ...string) []string {
+	seen := map[string]bool{}
+	issues := []string{}
+	report := func(issue string) {
+		if !seen[issue] {
+			seen[issue] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	// Walk variable references depth-first, tracking the current chain to find cycles
+	var visit func(name string, chain []string)
+	visit = func(name string, chain []string) {
+		for i, n := range chain {
+			if n == name {
+				cycle := append(append([]string{}, chain[i:]...), name)
+				report(fmt.Sprintf("{{%s}} is self-referential: %s", name, strings.Join(cycle, " -> ")))
+				return
+			}
+		}
+		value, ok := vars[name]
+		if !ok {
+			report(fmt.Sprintf("{{%s}} is not defined", name))
+			return
+		}
+		if strings.TrimSpace(value) == "" {
+			report(fmt.Sprintf("{{%s}} resolves to an empty value", name))
+			return
+		}
+		next := append(append([]string{}, chain...), name)
+		for _, ref := range referencedVariables(value) {
+			visit(ref, next)
+		}
+	}
+
+	for _, text := range texts {
+		for _, m := range variablePattern.FindAllStringSubmatch(text, -1) {
+			visit(m[1], nil)
+			if value, ok := vars[m[1]]; ok && m[2] != "" {
+				if _, err := typedLiteral(substituteVariables(value, vars), m[2]); err != nil {
+					report(fmt.Sprintf("{{%s:%s}} cannot be used: %v", m[1], m[2], err))
+				}
+			}
+		}
+	}
+	sort.Strings(issues)
+	return issues
+}
+
+// variableTriggered reports whether the text just before the cursor (row, rune column) is "{{"
+func variableTriggered(text string, row, col int) bool {
+	lines := strings.Split(text, "\n")
+	if row < 0 || row >= len(lines) {
+		return false
+	}
+	line := []rune(lines[row])
+	return col >= 2 && col <= len(line) && string(line[col-2:col]) == "{{"
+}
+
+// insertAtCursor inserts s at the cursor and returns the new text and cursor column
+func insertAtCursor(text string, row, col int, s string) (string, int) {
+	lines := strings.Split(text, "\n")
+	if row < 0 || row >= len(lines) {
+		return text + s, col
+	}
+	line := []rune(lines[row])
+	if col > len(line) {
+		col = len(line)
+	}
+	lines[row] = string(line[:col]) + s + string(line[col:])
+	return strings.Join(lines, "\n"), col + len([]rune(s))
+}
+
+// parseVariables reads KEY=VALUE lines; blank lines and # comments are skipped
+func parseVariables(text string) map[string]string {
+	vars := map[string]string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			vars[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return vars
+}
+
+func formatVariables(vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k + "=" + vars[k] + "\n")
+	}
+	return sb.String()
+}
+
+// Postman environment file format, used to share an environment alongside a collection
+type postmanEnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
+
+type postmanEnvironment struct {
+	Name   string                    `json:"name"`
+	Values []postmanEnvironmentValue `json:"values"`
+	Scope  string                    `json:"_postman_variable_scope"`
+}
+
+func toPostmanEnvironment(env Environment) postmanEnvironment {
+	keys := make([]string, 0, len(env.Variables))
+	for k := range env.Variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := postmanEnvironment{Name: env.Name, Values: []postmanEnvironmentValue{}, Scope: "environment"}
+	for _, k := range keys {
+		out.Values = append(out.Values, postmanEnvironmentValue{Key: k, Value: env.Variables[k], Enabled: true})
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// Extraction of response values into variables, one rule per line:
+//
+//	userId = $.data.id
+//	userId = body:$.data.id
+//	token = header:X-Auth-Token
+//
+// The source defaults to the body, read with JSONPath; header rules name a response header.
+
+const (
+	extractSourceBody   = "body"
+	extractSourceHeader = "header"
+)
+
+type extractionRule struct {
+	Variable string
+	Source   string
+	Expr     string
+}
+
+func parseExtractionRule(line string) (extractionRule, error) {
+	target, expr, ok := strings.Cut(line, "=")
+	target, expr = strings.TrimSpace(target), strings.TrimSpace(expr)
+	if !ok || target == "" || expr == "" {
+		return extractionRule{}, fmt.Errorf("expected variable = $.path or variable = header:Name")
+	}
+	rule := extractionRule{Variable: target, Source: extractSourceBody, Expr: expr}
+	if source, rest, ok := strings.Cut(expr, ":"); ok {
+		switch strings.ToLower(strings.TrimSpace(source)) {
+		case extractSourceHeader:
+			rule.Source, rule.Expr = extractSourceHeader, strings.TrimSpace(rest)
+		case extractSourceBody:
+			rule.Expr = strings.TrimSpace(rest)
+		}
+	}
+	return rule, nil
+}
+
+// extractValues applies the rules to a response. Values that are not strings are
+// stored as JSON. Lines that fail are reported and leave their variable unset.
+func extractValues(lines []string, header http.Header, body []byte) (map[string]string, []string) {
+	values := map[string]string{}
+	problems := []string{}
+	var data interface{}
+	bodyErr := json.Unmarshal(body, &data)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseExtractionRule(line)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", line, err))
+			continue
+		}
+		if rule.Source == extractSourceHeader {
+			if _, ok := header[http.CanonicalHeaderKey(rule.Expr)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: no %s header in the response", rule.Variable, rule.Expr))
+				continue
+			}
+			values[rule.Variable] = header.Get(rule.Expr)
+			continue
+		}
+		if bodyErr != nil {
+			problems = append(problems, fmt.Sprintf("%s: response is not valid JSON", rule.Variable))
+			continue
+		}
+		value, err := jsonpath.Get(rule.Expr, data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", rule.Variable, err))
+			continue
+		}
+		if s, ok := value.(string); ok {
+			values[rule.Variable] = s
+		} else {
+			encoded, _ := json.Marshal(value)
+			values[rule.Variable] = string(encoded)
+		}
+	}
+	return values, problems
+}
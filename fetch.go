@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Import of JavaScript fetch(url, {method, headers, body}) snippets,
+// as produced by "Copy as fetch" in browser devtools.
+
+type fetchOptions struct {
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    interface{}       `json:"body"`
+}
+
+// readJSString reads a quoted JS string starting at src[start] and returns its value and the index after it
+func readJSString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		c := src[i]
+		if c == '\\' && i+1 < len(src) {
+			i++
+			switch src[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if i+4 < len(src) {
+					var r rune
+					if _, err := fmt.Sscanf(src[i+1:i+5], "%04x", &r); err == nil {
+						sb.WriteRune(r)
+						i += 4
+						continue
+					}
+				}
+				sb.WriteByte('u')
+			default:
+				sb.WriteByte(src[i])
+			}
+			continue
+		}
+		if c == quote {
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteByte(c)
+	}
+	return "", 0, fmt.Errorf("unterminated string starting at offset %d", start)
+}
+
+// matchingClose returns the index of the bracket closing the one at src[start], skipping strings
+func matchingClose(src string, start int) (int, error) {
+	open := src[start]
+	closing := map[byte]byte{'{': '}', '[': ']', '(': ')'}[open]
+	depth := 0
+	for i := start; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '"', '\'', '`':
+			_, next, err := readJSString(src, i)
+			if err != nil {
+				return 0, err
+			}
+			i = next - 1
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no closing '%c' for '%c' at offset %d", closing, open, start)
+}
+
+func skipSpace(src string, i int) int {
+	for i < len(src) && unicode.IsSpace(rune(src[i])) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// readJSNumber reads the numeric literal starting at i, e.g. 42, 2.5E-3, .5, 0x1F or 1_000,
+// returning it as a JSON number and the index after it
+func readJSNumber(src string, i int) (string, int, error) {
+	hex := strings.HasPrefix(strings.ToLower(src[i:]), "0x")
+	j := i
+	for j < len(src) {
+		c := src[j]
+		exponentSign := (c == '+' || c == '-') && !hex && (src[j-1] == 'e' || src[j-1] == 'E')
+		if !isDigit(c) && c != '.' && c != '_' && !unicode.IsLetter(rune(c)) && !exponentSign {
+			break
+		}
+		j++
+	}
+	literal := strings.ReplaceAll(src[i:j], "_", "")
+	if json.Valid([]byte(literal)) {
+		return literal, j, nil
+	}
+	if n, err := strconv.ParseInt(literal, 0, 64); err == nil {
+		return strconv.FormatInt(n, 10), j, nil
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return strconv.FormatFloat(f, 'g', -1, 64), j, nil
+	}
+	return "", 0, fmt.Errorf("unsupported number '%s'", src[i:j])
+}
+
+// jsObjectToJSON loosely converts a JS object/array literal to JSON:
+// single-quoted and template strings, unquoted keys, trailing commas,
+// undefined, and JSON.stringify(...) values are accepted.
+func jsObjectToJSON(src string) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			s, next, err := readJSString(src, i)
+			if err != nil {
+				return "", err
+			}
+			quoted, _ := json.Marshal(s)
+			out.Write(quoted)
+			i = next
+		case c == ',':
+			// Drop trailing commas before a closing bracket
+			if j := skipSpace(src, i+1); j < len(src) && (src[j] == '}' || src[j] == ']') {
+				i++
+				continue
+			}
+			out.WriteByte(c)
+			i++
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			// A whole numeric literal, so the e in 1e5 isn't read as an identifier
+			number, next, err := readJSNumber(src, i)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(number)
+			i = next
+		case unicode.IsLetter(rune(c)) || c == '_' || c == '$':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_' || src[j] == '$' || src[j] == '.') {
+				j++
+			}
+			word := src[i:j]
+			next := skipSpace(src, j)
+			switch {
+			case next < len(src) && src[next] == ':':
+				quoted, _ := json.Marshal(word)
+				out.Write(quoted)
+			case word == "true" || word == "false" || word == "null":
+				out.WriteString(word)
+			case word == "undefined":
+				out.WriteString("null")
+			case word == "JSON.stringify" && next < len(src) && src[next] == '(':
+				end, err := matchingClose(src, next)
+				if err != nil {
+					return "", err
+				}
+				inner, err := jsObjectToJSON(strings.TrimSpace(src[next+1 : end]))
+				if err != nil {
+					return "", err
+				}
+				quoted, _ := json.Marshal(inner)
+				out.Write(quoted)
+				j = end + 1
+			default:
+				return "", fmt.Errorf("unsupported expression '%s'", word)
+			}
+			i = j
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String(), nil
+}
+
+// parseFetchSnippet extracts the request from a fetch(...) call
+func parseFetchSnippet(snippet string) (APIRequest, error) {
+	start := strings.Index(snippet, "fetch(")
+	if start == -1 {
+		return APIRequest{}, fmt.Errorf("no fetch( call found")
+	}
+	i := skipSpace(snippet, start+len("fetch("))
+	if i >= len(snippet) || !strings.ContainsRune("\"'`", rune(snippet[i])) {
+		return APIRequest{}, fmt.Errorf("the first fetch argument must be a quoted URL")
+	}
+	url, next, err := readJSString(snippet, i)
+	if err != nil {
+		return APIRequest{}, err
+	}
+	req := newAPIRequest("GET", url)
+
+	i = skipSpace(snippet, next)
+	if i < len(snippet) && snippet[i] == ',' {
+		i = skipSpace(snippet, i+1)
+		if i < len(snippet) && snippet[i] == '{' {
+			end, err := matchingClose(snippet, i)
+			if err != nil {
+				return APIRequest{}, err
+			}
+			converted, err := jsObjectToJSON(snippet[i : end+1])
+			if err != nil {
+				return APIRequest{}, fmt.Errorf("could not read fetch options: %v", err)
+			}
+			var opts fetchOptions
+			if err := json.Unmarshal([]byte(converted), &opts); err != nil {
+				return APIRequest{}, fmt.Errorf("could not read fetch options: %v", err)
+			}
+			if opts.Method != "" {
+				req.Method = strings.ToUpper(opts.Method)
+			}
+			for k, v := range opts.Headers {
+				req.Headers[k] = v
+			}
+			switch body := opts.Body.(type) {
+			case string:
+				req.Body = body
+			case nil:
+			default:
+				data, _ := json.Marshal(body)
+				req.Body = string(data)
+			}
+		}
+	}
+	return req, nil
+}
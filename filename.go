@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"mime"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// Extensions for common response types; mime.ExtensionsByType depends on the
+// system's MIME tables and may offer odd choices such as ".jfif" for JPEG
+var responseExtensions = map[string]string{
+	"application/json":         ".json",
+	"application/problem+json": ".json",
+	"application/x-ndjson":     ".ndjson",
+	"application/xml":          ".xml",
+	"text/xml":                 ".xml",
+	"application/pdf":          ".pdf",
+	"application/zip":          ".zip",
+	"application/gzip":         ".gz",
+	"text/csv":                 ".csv",
+	"text/html":                ".html",
+	"text/plain":               ".txt",
+	"text/markdown":            ".md",
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/gif":                ".gif",
+	"image/svg+xml":            ".svg",
+	"image/webp":               ".webp",
+}
+
+// sanitizeFileName keeps only the base name, so a server can't suggest a path outside the chosen folder
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "." || name == "/" || name == ".." {
+		return ""
+	}
+	return name
+}
+
+// responseFileName suggests a file name for a response body: the Content-Disposition
+// filename if the server sent one, otherwise the last segment of the URL path or
+// "response", with an extension from the Content-Type or the body itself
+func responseFileName(contentType, disposition, requestURL string, body []byte) string {
+	if _, params, err := mime.ParseMediaType(disposition); err == nil {
+		if name := sanitizeFileName(params["filename"]); name != "" {
+			return name
+		}
+	}
+	ext := ""
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		ext = responseExtensions[mediaType]
+		if ext == "" {
+			if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+				ext = exts[0]
+			}
+		}
+		if ext == "" && strings.HasSuffix(mediaType, "+json") {
+			ext = ".json"
+		}
+	}
+	if ext == "" {
+		switch {
+		case json.Valid(body):
+			ext = ".json"
+		case utf8.Valid(body):
+			ext = ".txt"
+		default:
+			ext = ".bin"
+		}
+	}
+	name := "response"
+	base, _, _ := splitURL(requestURL)
+	if _, p := splitRequestURL(base); p != "" {
+		if segment := sanitizeFileName(path.Base(p)); segment != "" && !strings.ContainsAny(segment, "{}") {
+			name = strings.TrimSuffix(segment, path.Ext(segment))
+		}
+	}
+	return name + ext
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flows: an ordered list of saved requests run one after another. After a step,
+// branches may send the flow to another step instead of the next one:
+//
+//	status >= 400 -> end; $.state == "pending" -> 2
+
+const (
+	flowConditionAlways = "Always"
+	flowCondition2xx    = "Previous 2xx"
+)
+
+var flowConditions = []string{flowConditionAlways, flowCondition2xx}
+
+type FlowStep struct {
+	Collection string `json:"collection"`
+	Request    string `json:"request"`
+	DelayMs    int    `json:"delayMs,omitempty"`
+	// Condition on the previous step's response for this step to run
+	Condition string `json:"condition,omitempty"`
+	// Checked in order after the step runs; the first match decides the next step
+	Branches []FlowBranch `json:"branches,omitempty"`
+}
+
+// FlowBranch jumps to step Goto (1-based, or "end") when the response matches When:
+// a status comparison such as "status == 404", or an assertion such as `$.done == true`
+type FlowBranch struct {
+	When string `json:"when"`
+	Goto string `json:"goto"`
+}
+
+const (
+	branchSeparator = ";"
+	branchArrow     = "->"
+	flowEnd         = "end"
+)
+
+// Runs stop after this many steps so a branch that loops back can't run forever
+const maxFlowSteps = 100
+
+// parseFlowBranches reads branches written as `when -> target; when -> target`
+// and checks each target against the number of steps
+func parseFlowBranches(text string, stepCount int) ([]FlowBranch, error) {
+	branches := []FlowBranch{}
+	for _, part := range strings.Split(text, branchSeparator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := strings.LastIndex(part, branchArrow)
+		if i == -1 {
+			return nil, fmt.Errorf("branch %q has no %s target", part, branchArrow)
+		}
+		when, target := strings.TrimSpace(part[:i]), strings.ToLower(strings.TrimSpace(part[i+len(branchArrow):]))
+		if when == "" {
+			return nil, fmt.Errorf("branch %q has no condition", part)
+		}
+		if target != flowEnd {
+			n, err := strconv.Atoi(target)
+			if err != nil || n < 1 || n > stepCount {
+				return nil, fmt.Errorf("branch %q: target must be a step number from 1 to %d or %q", part, stepCount, flowEnd)
+			}
+		}
+		branches = append(branches, FlowBranch{When: when, Goto: target})
+	}
+	return branches, nil
+}
+
+func formatFlowBranches(branches []FlowBranch) string {
+	parts := []string{}
+	for _, b := range branches {
+		parts = append(parts, b.When+" "+branchArrow+" "+b.Goto)
+	}
+	return strings.Join(parts, branchSeparator+" ")
+}
+
+// branchMatches checks a branch condition against a step's outcome; status is 0 when the request failed
+func branchMatches(when string, status int, body []byte) (bool, error) {
+	left, op, right, ok := splitAssertion(when)
+	if !ok {
+		return false, fmt.Errorf("no comparison operator in %q", when)
+	}
+	if strings.EqualFold(left, "status") {
+		expected, err := strconv.Atoi(right)
+		if err != nil {
+			return false, fmt.Errorf("%q is not a status code", right)
+		}
+		return compareValues(float64(status), op, float64(expected))
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return false, fmt.Errorf("response is not valid JSON")
+	}
+	result := evaluateAssertion(when, data)
+	if !result.Passed && result.Detail != "" && !strings.HasPrefix(result.Detail, "actual:") {
+		return false, fmt.Errorf("%s", result.Detail)
+	}
+	return result.Passed, nil
+}
+
+type Flow struct {
+	Name  string     `json:"name"`
+	Steps []FlowStep `json:"steps"`
+}
+
+func findRequest(ws Workspace, collection, name string) (APIRequest, bool) {
+	for _, col := range ws.Collections {
+		if col.Name != collection {
+			continue
+		}
+		for _, r := range col.allRequests() {
+			if r.Name == name {
+				return r, true
+			}
+		}
+	}
+	return APIRequest{}, false
+}
+
+// renameFlowCollection points the flow steps of collection from at its new name
+func (ws *Workspace) renameFlowCollection(from, to string) {
+	for i := range ws.Flows {
+		for j := range ws.Flows[i].Steps {
+			if ws.Flows[i].Steps[j].Collection == from {
+				ws.Flows[i].Steps[j].Collection = to
+			}
+		}
+	}
+}
+
+// runFlow executes the flow's steps in order, with cookies from jar, and reports progress through logf.
+// resolve prepares each request for sending (variable substitution) and is given the name
+// of the step's collection; extract stores values from each response for later steps and returns its problems.
+func runFlow(ws Workspace, flow Flow, jar http.CookieJar, resolve func(collection string, r APIRequest) APIRequest,
+	extract func(APIRequest, *exchangeResult) []string, logf func(format string, args ...interface{})) {
+	logf("Running flow '%s' (%d steps)", flow.Name, len(flow.Steps))
+	prevStatus := 0
+	for i, executed := 0, 0; i < len(flow.Steps); executed++ {
+		if executed == maxFlowSteps {
+			logf("Stopped after %d steps; check the branches for a loop", maxFlowSteps)
+			return
+		}
+		step := flow.Steps[i]
+		label := fmt.Sprintf("[%d/%d] %s / %s", i+1, len(flow.Steps), step.Collection, step.Request)
+		if i > 0 && step.Condition == flowCondition2xx && (prevStatus < 200 || prevStatus >= 300) {
+			logf("%s: stopped, previous step did not return 2xx", label)
+			return
+		}
+		if step.DelayMs > 0 {
+			logf("%s: waiting %d ms", label, step.DelayMs)
+			time.Sleep(time.Duration(step.DelayMs) * time.Millisecond)
+		}
+		r, ok := findRequest(ws, step.Collection, step.Request)
+		if !ok {
+			logf("%s: request not found, stopping", label)
+			return
+		}
+		r = resolve(step.Collection, r)
+		res, err := executeRequest(r, jar)
+		var body []byte
+		if err != nil {
+			prevStatus = 0
+			logf("%s: %s %s failed: %v", label, r.Method, r.URL, err)
+		} else {
+			prevStatus, body = res.StatusCode, res.Body
+			logf("%s: %s %s -> %s (%d ms, %s)", label, r.Method, r.URL, res.Status,
+				res.Elapsed.Milliseconds(), formatSize(len(res.Body)))
+			for _, problem := range extract(r, res) {
+				logf("%s: extract %s", label, problem)
+			}
+		}
+		next := i + 1
+		for _, branch := range step.Branches {
+			matched, err := branchMatches(branch.When, prevStatus, body)
+			if err != nil {
+				logf("%s: branch '%s' skipped: %v", label, branch.When, err)
+				continue
+			}
+			if !matched {
+				continue
+			}
+			if branch.Goto == flowEnd {
+				logf("%s: '%s' matched, ending the flow", label, branch.When)
+				logf("Flow finished")
+				return
+			}
+			next, _ = strconv.Atoi(branch.Goto)
+			next--
+			logf("%s: '%s' matched, going to step %d", label, branch.When, next+1)
+			break
+		}
+		i = next
+	}
+	logf("Flow finished")
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Folders group the requests of a collection and may hold subfolders of their own.
+// The rest of the app addresses a request by one index over the whole collection:
+// folders come first, each with its subfolders before its own requests, then the
+// requests outside any folder. Saving a new request appends it at the end, so the
+// indices of the other requests don't change.
+
+type Folder struct {
+	Name     string       `json:"name"`
+	Requests []APIRequest `json:"requests,omitempty"`
+	Folders  []Folder     `json:"folders,omitempty"`
+}
+
+// requestSlot is where a request of the collection is stored
+type requestSlot struct {
+	// Folder holding the request, as indices from the collection down; nil outside any folder
+	folder []int
+	list   *[]APIRequest
+	pos    int
+}
+
+func appendSlots(slots []requestSlot, path []int, folders []Folder, requests *[]APIRequest) []requestSlot {
+	for i := range folders {
+		sub := append(append([]int{}, path...), i)
+		slots = appendSlots(slots, sub, folders[i].Folders, &folders[i].Requests)
+	}
+	for i := range *requests {
+		slots = append(slots, requestSlot{folder: path, list: requests, pos: i})
+	}
+	return slots
+}
+
+// slots lists where each request of the collection is stored, by index
+func (c *Collection) slots() []requestSlot {
+	return appendSlots(nil, nil, c.Folders, &c.Requests)
+}
+
+func (c *Collection) requestCount() int {
+	return len(c.slots())
+}
+
+// request returns the request at idx, or nil if there is none
+func (c *Collection) request(idx int) *APIRequest {
+	slots := c.slots()
+	if idx < 0 || idx >= len(slots) {
+		return nil
+	}
+	return &(*slots[idx].list)[slots[idx].pos]
+}
+
+// allRequests returns a copy of every request of the collection, by index
+func (c *Collection) allRequests() []APIRequest {
+	requests := []APIRequest{}
+	for _, s := range c.slots() {
+		requests = append(requests, (*s.list)[s.pos])
+	}
+	return requests
+}
+
+// setAllRequests replaces the requests with edited copies from allRequests
+func (c *Collection) setAllRequests(requests []APIRequest) {
+	for i, s := range c.slots() {
+		if i < len(requests) {
+			(*s.list)[s.pos] = requests[i]
+		}
+	}
+}
+
+// removeRequest deletes the request at idx; later requests move up one index
+func (c *Collection) removeRequest(idx int) {
+	slots := c.slots()
+	if idx < 0 || idx >= len(slots) {
+		return
+	}
+	list, pos := slots[idx].list, slots[idx].pos
+	*list = append((*list)[:pos], (*list)[pos+1:]...)
+}
+
+// swapRequest swaps the request at idx with its neighbour delta places away in the
+// same folder, returning the neighbour's index; ok is false when there is none
+func (c *Collection) swapRequest(idx, delta int) (other int, ok bool) {
+	slots := c.slots()
+	if idx < 0 || idx >= len(slots) {
+		return 0, false
+	}
+	list, pos := slots[idx].list, slots[idx].pos
+	if pos+delta < 0 || pos+delta >= len(*list) {
+		return 0, false
+	}
+	(*list)[pos], (*list)[pos+delta] = (*list)[pos+delta], (*list)[pos]
+	return idx + delta, true
+}
+
+// siblings returns the position of the request at idx within its folder and the
+// number of requests there
+func (c *Collection) siblings(idx int) (pos, count int) {
+	slots := c.slots()
+	if idx < 0 || idx >= len(slots) {
+		return 0, 0
+	}
+	return slots[idx].pos, len(*slots[idx].list)
+}
+
+// requestFolder returns the folder path of the request at idx
+func (c *Collection) requestFolder(idx int) []int {
+	slots := c.slots()
+	if idx < 0 || idx >= len(slots) {
+		return nil
+	}
+	return slots[idx].folder
+}
+
+// folderAt returns the folder at path, or nil if there is none
+func (c *Collection) folderAt(path []int) *Folder {
+	folders := &c.Folders
+	var folder *Folder
+	for _, i := range path {
+		if i < 0 || i >= len(*folders) {
+			return nil
+		}
+		folder = &(*folders)[i]
+		folders = &folder.Folders
+	}
+	return folder
+}
+
+// addRequest appends r to the folder at path (nil for outside any folder) and
+// returns its index; requests from that index on move down one
+func (c *Collection) addRequest(path []int, r APIRequest) int {
+	if len(path) == 0 {
+		c.Requests = append(c.Requests, r)
+		return c.requestCount() - 1
+	}
+	folder := c.folderAt(path)
+	if folder == nil {
+		return -1
+	}
+	folder.Requests = append(folder.Requests, r)
+	last := -1
+	for i, s := range c.slots() {
+		if samePath(s.folder, path) {
+			last = i
+		}
+	}
+	return last
+}
+
+// insertRequest puts r at position pos of the folder at path (nil for outside any
+// folder), undoing a removeRequest
+func (c *Collection) insertRequest(path []int, pos int, r APIRequest) {
+	list := &c.Requests
+	if len(path) > 0 {
+		folder := c.folderAt(path)
+		if folder == nil {
+			return
+		}
+		list = &folder.Requests
+	}
+	if pos < 0 || pos > len(*list) {
+		pos = len(*list)
+	}
+	*list = append((*list)[:pos], append([]APIRequest{r}, (*list)[pos:]...)...)
+}
+
+// addFolder appends an empty folder under parent (nil for the top level)
+func (c *Collection) addFolder(parent []int, name string) bool {
+	if len(parent) == 0 {
+		c.Folders = append(c.Folders, Folder{Name: name})
+		return true
+	}
+	folder := c.folderAt(parent)
+	if folder == nil {
+		return false
+	}
+	folder.Folders = append(folder.Folders, Folder{Name: name})
+	return true
+}
+
+// removeFolder deletes the folder at path with everything in it, returning the
+// index of its first request and how many requests went with it
+func (c *Collection) removeFolder(path []int) (first, count int) {
+	if len(path) == 0 || c.folderAt(path) == nil {
+		return 0, 0
+	}
+	first = -1
+	for i, s := range c.slots() {
+		if isWithin(s.folder, path) {
+			if first == -1 {
+				first = i
+			}
+			count++
+		}
+	}
+	folders := &c.Folders
+	if parent := c.folderAt(path[:len(path)-1]); parent != nil {
+		folders = &parent.Folders
+	}
+	i := path[len(path)-1]
+	*folders = append((*folders)[:i], (*folders)[i+1:]...)
+	return first, count
+}
+
+// folderPaths lists every folder of the collection in tree order, with labels like "Users / Admin"
+func (c *Collection) folderPaths() (paths [][]int, labels []string) {
+	var walk func(folders []Folder, path []int, label string)
+	walk = func(folders []Folder, path []int, label string) {
+		for i, f := range folders {
+			sub := append(append([]int{}, path...), i)
+			name := f.Name
+			if label != "" {
+				name = label + " / " + f.Name
+			}
+			paths = append(paths, sub)
+			labels = append(labels, name)
+			walk(f.Folders, sub, name)
+		}
+	}
+	walk(c.Folders, nil, "")
+	return paths, labels
+}
+
+// folderNames returns the names of the folder at path and of its parents, from the top down
+func (c *Collection) folderNames(path []int) []string {
+	names := []string{}
+	for i := 1; i <= len(path); i++ {
+		folder := c.folderAt(path[:i])
+		if folder == nil {
+			return nil
+		}
+		names = append(names, folder.Name)
+	}
+	return names
+}
+
+// folderByNames finds a folder by the names from folderNames; ok is false when there is none
+func (c *Collection) folderByNames(names []string) (path []int, ok bool) {
+	folders := c.Folders
+	for _, name := range names {
+		found := -1
+		for i, f := range folders {
+			if f.Name == name {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			return nil, false
+		}
+		path = append(path, found)
+		folders = folders[found].Folders
+	}
+	return path, len(path) > 0
+}
+
+// setFolderOpen records whether the folder named by names is open in the request tree
+func (c *Collection) setFolderOpen(names []string, open bool) {
+	kept := [][]string{}
+	for _, f := range c.OpenFolders {
+		if !sameNames(f, names) {
+			kept = append(kept, f)
+		}
+	}
+	if open {
+		kept = append(kept, names)
+	}
+	c.OpenFolders = kept
+}
+
+// renameOpenFolders keeps the open folders recorded after the folder named by old is
+// renamed to name, including its subfolders
+func (c *Collection) renameOpenFolders(old []string, name string) {
+	for _, f := range c.OpenFolders {
+		if len(f) >= len(old) && sameNames(f[:len(old)], old) {
+			f[len(old)-1] = name
+		}
+	}
+}
+
+// forgetOpenFolders drops the folder named by names, and its subfolders, from the open folders
+func (c *Collection) forgetOpenFolders(names []string) {
+	kept := [][]string{}
+	for _, f := range c.OpenFolders {
+		if len(f) < len(names) || !sameNames(f[:len(names)], names) {
+			kept = append(kept, f)
+		}
+	}
+	c.OpenFolders = kept
+}
+
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func samePath(a, b []int) bool {
+	return len(a) == len(b) && isWithin(a, b)
+}
+
+// isWithin reports whether folder path is folder or one of its subfolders
+func isWithin(path, folder []int) bool {
+	if len(path) < len(folder) {
+		return false
+	}
+	for i := range folder {
+		if path[i] != folder[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Node IDs of the request tree: "f" and a folder path like "0.2", or "r" and a request index
+
+func folderNodeID(path []int) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(p)
+	}
+	return "f" + strings.Join(parts, ".")
+}
+
+func requestNodeID(idx int) string {
+	return "r" + strconv.Itoa(idx)
+}
+
+// parseNodeID reads a tree node ID; the root node "" is the top-level folder
+func parseNodeID(id string) (path []int, reqIdx int, isFolder bool) {
+	if id == "" {
+		return nil, -1, true
+	}
+	if rest, ok := strings.CutPrefix(id, "r"); ok {
+		idx, err := strconv.Atoi(rest)
+		if err != nil {
+			return nil, -1, false
+		}
+		return nil, idx, false
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(id, "f"), ".") {
+		i, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, -1, false
+		}
+		path = append(path, i)
+	}
+	return path, -1, true
+}
+
+// treeChildren returns the node IDs under a folder node: subfolders, then requests
+func (c *Collection) treeChildren(id string) []string {
+	path, _, isFolder := parseNodeID(id)
+	if !isFolder {
+		return nil
+	}
+	folders := c.Folders
+	if len(path) > 0 {
+		folder := c.folderAt(path)
+		if folder == nil {
+			return nil
+		}
+		folders = folder.Folders
+	}
+	children := []string{}
+	for i := range folders {
+		children = append(children, folderNodeID(append(append([]int{}, path...), i)))
+	}
+	for i, s := range c.slots() {
+		if samePath(s.folder, path) {
+			children = append(children, requestNodeID(i))
+		}
+	}
+	return children
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Form bodies built from key/value fields. In multipart mode a field may be a
+// file, stored as its path and read when the request is sent; in URL-encoded
+// mode every field is sent as text.
+
+type FormField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Value is the path of a file to upload
+	IsFile bool `json:"file,omitempty"`
+	// Content-Type of the part in a multipart body, e.g. application/json; files
+	// default to a type guessed from their extension, text to none
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// formFieldLines describes the fields one per line, files as key=@path, with
+// ;type= and the content type as in curl -F when a field has one
+func formFieldLines(fields []FormField) []string {
+	lines := []string{}
+	for _, f := range fields {
+		line := f.Key + "=" + f.Value
+		if f.IsFile {
+			line = f.Key + "=@" + f.Value
+		}
+		if f.ContentType != "" {
+			line += ";type=" + f.ContentType
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// encodeFormFields encodes the fields as application/x-www-form-urlencoded.
+// Repeated keys are all sent, and a field with a key but no value is sent as "key=".
+func encodeFormFields(fields []FormField) string {
+	values := url.Values{}
+	for _, f := range fields {
+		if f.Key == "" && f.Value == "" {
+			continue
+		}
+		values.Add(f.Key, f.Value)
+	}
+	return values.Encode()
+}
+
+// lazyFile opens its file on the first read, so a request that is built but
+// never sent holds no file open
+type lazyFile struct {
+	path string
+	f    *os.File
+}
+
+func (l *lazyFile) Read(p []byte) (int, error) {
+	if l.f == nil {
+		f, err := os.Open(l.path)
+		if err != nil {
+			return 0, err
+		}
+		l.f = f
+	}
+	return l.f.Read(p)
+}
+
+type multipartBody struct {
+	io.Reader
+	files []*lazyFile
+}
+
+func (b *multipartBody) Close() error {
+	for _, l := range b.files {
+		if l.f != nil {
+			l.f.Close()
+		}
+	}
+	return nil
+}
+
+// newMultipartBody lays out a multipart/form-data body with the given boundary.
+// Files are streamed from disk rather than read up front, and their sizes are
+// taken from the file system so the total length is known before sending.
+func newMultipartBody(fields []FormField, boundary string) (*multipartBody, int64, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return nil, 0, err
+	}
+	body := &multipartBody{}
+	segments := []io.Reader{}
+	var length int64
+	for _, f := range fields {
+		if f.Key == "" && f.Value == "" {
+			continue
+		}
+		if !f.IsFile {
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(f.Key)))
+			if f.ContentType != "" {
+				h.Set("Content-Type", f.ContentType)
+			}
+			part, err := mw.CreatePart(h)
+			if err != nil {
+				return nil, 0, err
+			}
+			if _, err := io.WriteString(part, f.Value); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		info, err := os.Stat(f.Value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("form field %q: %v", f.Key, err)
+		}
+		if info.IsDir() {
+			return nil, 0, fmt.Errorf("form field %q: %s is a directory", f.Key, f.Value)
+		}
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(f.Value))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.Key), escapeQuotes(filepath.Base(f.Value))))
+		h.Set("Content-Type", contentType)
+		if _, err := mw.CreatePart(h); err != nil {
+			return nil, 0, err
+		}
+		// The part header written so far, then the file itself
+		segments = append(segments, bytes.NewReader(append([]byte{}, buf.Bytes()...)))
+		length += int64(buf.Len()) + info.Size()
+		buf.Reset()
+		file := &lazyFile{path: f.Value}
+		body.files = append(body.files, file)
+		segments = append(segments, file)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, 0, err
+	}
+	segments = append(segments, bytes.NewReader(buf.Bytes()))
+	length += int64(buf.Len())
+	body.Reader = io.MultiReader(segments...)
+	return body, length, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// newMultipartRequest builds a request with a multipart/form-data body of the
+// request's form fields. It returns the content type, which carries the boundary.
+func newMultipartRequest(r APIRequest) (*http.Request, int, string, error) {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	body, length, err := newMultipartBody(r.FormFields, boundary)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	req, err := http.NewRequest(r.Method, r.URL, body)
+	if err != nil {
+		body.Close()
+		return nil, 0, "", err
+	}
+	req.ContentLength = length
+	// Lets the body be sent again when following a 307/308 redirect
+	req.GetBody = func() (io.ReadCloser, error) {
+		again, _, err := newMultipartBody(r.FormFields, boundary)
+		return again, err
+	}
+	return req, int(length), "multipart/form-data; boundary=" + boundary, nil
+}
+
+// Request body in Postman collection v2.1 format; files in form data are referenced by path in src
+type postmanFormField struct {
+	Key         string `json:"key"`
+	Value       string `json:"value,omitempty"`
+	Type        string `json:"type"`
+	Src         string `json:"src,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string             `json:"mode"`
+	Raw        string             `json:"raw,omitempty"`
+	FormData   []postmanFormField `json:"formdata,omitempty"`
+	URLEncoded []postmanFormField `json:"urlencoded,omitempty"`
+}
+
+func toPostmanBody(r APIRequest) postmanBody {
+	switch r.BodyMode {
+	case bodyModeMultipart:
+		body := postmanBody{Mode: "formdata", FormData: []postmanFormField{}}
+		for _, f := range r.FormFields {
+			if f.IsFile {
+				body.FormData = append(body.FormData, postmanFormField{Key: f.Key, Type: "file", Src: f.Value, ContentType: f.ContentType})
+			} else {
+				body.FormData = append(body.FormData, postmanFormField{Key: f.Key, Value: f.Value, Type: "text", ContentType: f.ContentType})
+			}
+		}
+		return body
+	case bodyModeURLEncoded:
+		body := postmanBody{Mode: "urlencoded", URLEncoded: []postmanFormField{}}
+		for _, f := range r.FormFields {
+			body.URLEncoded = append(body.URLEncoded, postmanFormField{Key: f.Key, Value: f.Value, Type: "text"})
+		}
+		return body
+	}
+	return postmanBody{Mode: "raw", Raw: r.Body}
+}
+
+// applyTo sets the body of r; modes other than raw, form data and URL-encoded are ignored
+func (b postmanBody) applyTo(r *APIRequest) {
+	switch b.Mode {
+	case "formdata":
+		r.BodyMode = bodyModeMultipart
+		for _, f := range b.FormData {
+			if f.Type == "file" {
+				r.FormFields = append(r.FormFields, FormField{Key: f.Key, Value: f.Src, IsFile: true, ContentType: f.ContentType})
+			} else {
+				r.FormFields = append(r.FormFields, FormField{Key: f.Key, Value: f.Value, ContentType: f.ContentType})
+			}
+		}
+	case "urlencoded":
+		r.BodyMode = bodyModeURLEncoded
+		for _, f := range b.URLEncoded {
+			r.FormFields = append(r.FormFields, FormField{Key: f.Key, Value: f.Value})
+		}
+	default:
+		r.Body = b.Raw
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Generation of Go type definitions from a JSON response, as starter code.
+// The shape of every value is inferred first; elements of an array are merged
+// into one shape, so a field missing from some of them is tagged omitempty.
+
+type jsonShape struct {
+	Kind string // string, int, float, bool, null, object, array or mixed
+	// Object fields, with the number of objects each appeared in
+	Fields  map[string]*jsonShape
+	Present map[string]int
+	Count   int
+	// Merged shape of array elements; nil for an empty array
+	Elem *jsonShape
+}
+
+func inferShape(value interface{}) *jsonShape {
+	switch v := value.(type) {
+	case string:
+		return &jsonShape{Kind: "string"}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &jsonShape{Kind: "int"}
+		}
+		return &jsonShape{Kind: "float"}
+	case bool:
+		return &jsonShape{Kind: "bool"}
+	case map[string]interface{}:
+		s := &jsonShape{Kind: "object", Fields: map[string]*jsonShape{}, Present: map[string]int{}, Count: 1}
+		for k, fv := range v {
+			s.Fields[k] = inferShape(fv)
+			s.Present[k] = 1
+		}
+		return s
+	case []interface{}:
+		s := &jsonShape{Kind: "array"}
+		for _, ev := range v {
+			s.Elem = mergeShapes(s.Elem, inferShape(ev))
+		}
+		return s
+	}
+	return &jsonShape{Kind: "null"}
+}
+
+func mergeShapes(a, b *jsonShape) *jsonShape {
+	switch {
+	case a == nil || a.Kind == "null":
+		return b
+	case b == nil || b.Kind == "null":
+		return a
+	case a.Kind == b.Kind:
+		switch a.Kind {
+		case "object":
+			for k, fb := range b.Fields {
+				a.Fields[k] = mergeShapes(a.Fields[k], fb)
+				a.Present[k] += b.Present[k]
+			}
+			a.Count += b.Count
+		case "array":
+			a.Elem = mergeShapes(a.Elem, b.Elem)
+		}
+		return a
+	case (a.Kind == "int" && b.Kind == "float") || (a.Kind == "float" && b.Kind == "int"):
+		return &jsonShape{Kind: "float"}
+	}
+	return &jsonShape{Kind: "mixed"}
+}
+
+// Words written in upper case in Go identifiers
+var goInitialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "API": true, "HTTP": true, "HTTPS": true, "JSON": true,
+	"XML": true, "UUID": true, "IP": true, "SQL": true, "HTML": true, "CSS": true, "UI": true,
+}
+
+// goIdentifier turns a JSON key such as "user_id" or "created-at" into an exported name ("UserID", "CreatedAt")
+func goIdentifier(key string) string {
+	var sb strings.Builder
+	words := strings.FieldsFunc(key, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for _, word := range words {
+		if goInitialisms[strings.ToUpper(word)] {
+			sb.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		sb.WriteRune(unicode.ToUpper(runes[0]))
+		sb.WriteString(string(runes[1:]))
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "Field" + name
+	}
+	return name
+}
+
+// singular guesses the element name of a plural field, e.g. "Items" -> "Item"
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name + "Item"
+}
+
+type goStructGenerator struct {
+	out   bytes.Buffer
+	taken map[string]bool
+	queue []func()
+}
+
+func (g *goStructGenerator) uniqueName(name string) string {
+	unique := name
+	for n := 2; g.taken[unique]; n++ {
+		unique = fmt.Sprintf("%s%d", name, n)
+	}
+	g.taken[unique] = true
+	return unique
+}
+
+// typeFor returns the Go type for a shape, queueing struct definitions for objects
+func (g *goStructGenerator) typeFor(s *jsonShape, name string) string {
+	if s == nil {
+		return "interface{}"
+	}
+	switch s.Kind {
+	case "string", "bool":
+		return s.Kind
+	case "int":
+		return "int64"
+	case "float":
+		return "float64"
+	case "array":
+		return "[]" + g.typeFor(s.Elem, singular(name))
+	case "object":
+		name = g.uniqueName(name)
+		g.queue = append(g.queue, func() { g.writeStruct(name, s) })
+		return name
+	}
+	return "interface{}"
+}
+
+func (g *goStructGenerator) writeStruct(name string, s *jsonShape) {
+	keys := make([]string, 0, len(s.Fields))
+	for k := range s.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(&g.out, "type %s struct {\n", name)
+	fieldNames := map[string]bool{}
+	for _, k := range keys {
+		field := goIdentifier(k)
+		unique := field
+		for n := 2; fieldNames[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", field, n)
+		}
+		fieldNames[unique] = true
+		tag, typ := k, g.typeFor(s.Fields[k], unique)
+		if s.Present[k] < s.Count {
+			tag += ",omitempty"
+			// omitempty has no effect on struct values
+			if s.Fields[k] != nil && s.Fields[k].Kind == "object" {
+				typ = "*" + typ
+			}
+		}
+		fmt.Fprintf(&g.out, "%s %s `json:%q`\n", unique, typ, tag)
+	}
+	g.out.WriteString("}\n\n")
+}
+
+// generateGoStructs returns Go type definitions for the JSON document in data,
+// the top-level type named rootName
+func generateGoStructs(data []byte, rootName string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("the response is not valid JSON: %v", err)
+	}
+	g := &goStructGenerator{taken: map[string]bool{}}
+	shape := inferShape(value)
+	if shape.Kind == "object" {
+		g.typeFor(shape, rootName)
+	} else {
+		name := g.uniqueName(rootName)
+		// Reserve the name before the element type can claim it
+		fmt.Fprintf(&g.out, "type %s %s\n\n", name, g.typeFor(shape, rootName))
+	}
+	for len(g.queue) > 0 {
+		next := g.queue[0]
+		g.queue = g.queue[1:]
+		next()
+	}
+	src, err := format.Source(g.out.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(src), nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import "strings"
+
+// Header name suggestions for the raw headers editor
+
+var commonHeaderNames = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Authorization",
+	"Cache-Control",
+	"Connection",
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Type",
+	"Cookie",
+	"If-Match",
+	"If-Modified-Since",
+	"If-None-Match",
+	"Origin",
+	"Range",
+	"Referer",
+	"User-Agent",
+	"X-API-Key",
+	"X-Correlation-ID",
+	"X-Forwarded-For",
+	"X-Request-ID",
+}
+
+// Accept-Language values offered in the request settings; any other value can be typed
+var commonLocales = []string{
+	"en-US",
+	"en-GB",
+	"de-DE",
+	"es-ES",
+	"fr-FR",
+	"it-IT",
+	"ja-JP",
+	"ko-KR",
+	"pt-BR",
+	"zh-CN",
+	"ar",
+	"fr-CA, fr;q=0.9, en;q=0.8",
+}
+
+// Number of recently used header names remembered across sessions
+const maxRecentHeaders = 20
+
+// headerSuggestions returns up to limit header names starting with prefix
+// (case-insensitive), recently used names first. An exact match is not suggested.
+func headerSuggestions(prefix string, recent []string, limit int) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	out := []string{}
+	for _, name := range append(append([]string{}, recent...), commonHeaderNames...) {
+		lower := strings.ToLower(name)
+		if seen[lower] || lower == prefix || !strings.HasPrefix(lower, prefix) {
+			continue
+		}
+		seen[lower] = true
+		out = append(out, name)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// rememberHeaders moves names to the front of the recent list, dropping duplicates and old entries
+func rememberHeaders(recent, names []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, name := range append(append([]string{}, names...), recent...) {
+		lower := strings.ToLower(name)
+		if name == "" || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		out = append(out, name)
+	}
+	if len(out) > maxRecentHeaders {
+		out = out[:maxRecentHeaders]
+	}
+	return out
+}
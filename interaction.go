@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A full request/response exchange, exported for bug reports
+
+type interactionRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Host    string      `json:"host,omitempty"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body"`
+}
+
+type interactionResponse struct {
+	Status     string      `json:"status"`
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers"`
+	Body       string      `json:"body"`
+	DurationMs int64       `json:"durationMs"`
+	Size       int         `json:"size"`
+}
+
+type interaction struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Request   interactionRequest  `json:"request"`
+	Response  interactionResponse `json:"response"`
+}
+
+func formatHeaderBlock(headers http.Header) string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", k, strings.Join(headers[k], ", ")))
+	}
+	return sb.String()
+}
+
+func (i interaction) markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# HTTP Interaction\n\n")
+	sb.WriteString(fmt.Sprintf("Captured: %s\n\n", i.Timestamp.Format(time.RFC3339)))
+
+	sb.WriteString("## Request\n\n")
+	sb.WriteString(fmt.Sprintf("`%s %s`\n\n", i.Request.Method, i.Request.URL))
+	if i.Request.Host != "" {
+		sb.WriteString(fmt.Sprintf("Host override: `%s`\n\n", i.Request.Host))
+	}
+	sb.WriteString("### Headers\n\n```\n" + formatHeaderBlock(i.Request.Headers) + "```\n\n")
+	if i.Request.Body != "" {
+		sb.WriteString("### Body\n\n```\n" + i.Request.Body + "\n```\n\n")
+	}
+
+	sb.WriteString("## Response\n\n")
+	sb.WriteString(fmt.Sprintf("Status: `%s`  \nTime: %d ms  \nSize: %s\n\n",
+		i.Response.Status, i.Response.DurationMs, formatSize(i.Response.Size)))
+	sb.WriteString("### Headers\n\n```\n" + formatHeaderBlock(i.Response.Headers) + "```\n\n")
+	sb.WriteString("### Body\n\n```\n" + i.Response.Body + "\n```\n")
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JUnit XML report of a collection run, for CI servers that show test results natively.
+// Each request is a test case: a request that couldn't be sent is an error, a 4xx/5xx
+// answer or a failed assertion is a failure, and a disabled request is skipped.
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSeconds formats a duration the way JUnit reports expect, in seconds
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// junitTestCaseFor turns the result of one request into a test case of collection
+func junitTestCaseFor(collection string, res runResult) junitTestCase {
+	tc := junitTestCase{
+		Name:      res.Method + " " + res.Name,
+		ClassName: collection,
+		Time:      junitSeconds(res.Elapsed),
+	}
+	switch {
+	case res.Skipped:
+		tc.Skipped = &junitMessage{Message: "request is disabled"}
+		return tc
+	case res.Err != nil:
+		tc.Error = &junitMessage{Message: res.Err.Error(), Text: res.URL}
+		return tc
+	}
+	out := []string{res.Method + " " + res.URL, "Status: " + res.Status}
+	for _, problem := range res.Problems {
+		out = append(out, "Extract: "+problem)
+	}
+	tc.SystemOut = strings.Join(out, "\n")
+	if !res.failed() {
+		return tc
+	}
+	failed := []string{}
+	for _, t := range res.Tests {
+		if !t.Passed {
+			failed = append(failed, fmt.Sprintf("%s (%s)", t.Assertion, t.Detail))
+		}
+	}
+	message := fmt.Sprintf("%d of %d tests failed", len(failed), len(res.Tests))
+	if res.StatusCode >= 400 {
+		message = "HTTP " + res.Status
+	}
+	tc.Failure = &junitMessage{Message: message, Text: strings.Join(failed, "\n")}
+	return tc
+}
+
+// junitReport encodes the results of a run of collection started at started.
+// Requests the run didn't get to (nil results) are left out.
+func junitReport(collection string, started time.Time, results []*runResult) ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      collection,
+		Timestamp: started.Format("2006-01-02T15:04:05"),
+		Cases:     []junitTestCase{},
+	}
+	var total time.Duration
+	for _, res := range results {
+		if res == nil {
+			continue
+		}
+		tc := junitTestCaseFor(collection, *res)
+		switch {
+		case tc.Skipped != nil:
+			suite.Skipped++
+		case tc.Error != nil:
+			suite.Errors++
+		case tc.Failure != nil:
+			suite.Failures++
+		}
+		total += res.Elapsed
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+	suite.Time = junitSeconds(total)
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
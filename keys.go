@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// A focused Entry receives every shortcut typed while it has focus, so window
+// shortcuts registered on the canvas never fire while editing. shortcutEntry
+// offers each shortcut to onShortcut first and only falls back to the Entry's
+// own handling (copy, paste, word movement...) when it was not consumed.
+type shortcutEntry struct {
+	widget.Entry
+	onShortcut func(fyne.Shortcut) bool
+}
+
+func newShortcutEntry(multiLine bool) *shortcutEntry {
+	e := &shortcutEntry{}
+	e.MultiLine = multiLine
+	e.Wrapping = fyne.TextTruncate
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *shortcutEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if e.onShortcut != nil && e.onShortcut(shortcut) {
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+// isCustomShortcut reports whether shortcut is the key combination key+modifier
+func isCustomShortcut(shortcut fyne.Shortcut, key fyne.KeyName, modifier fyne.KeyModifier) bool {
+	custom, ok := shortcut.(*desktop.CustomShortcut)
+	return ok && custom.KeyName == key && custom.Modifier == modifier
+}
@@ -2,13 +2,20 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"mime"
 	"net/http"
-	"os"
-	"path/filepath"
+	"net/http/httputil"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"image/color"
@@ -18,6 +25,7 @@ import (
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
@@ -32,51 +40,118 @@ type APIRequest struct {
 	URL     string            `json:"url"`
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"`
+	Host    string            `json:"host,omitempty"`
+	// Authentication sent as the Authorization header unless the headers set one
+	Auth *RequestAuth `json:"auth,omitempty"`
+	// Header lines commented out with // or #, kept so they survive save/load
+	CommentedHeaders []string `json:"commentedHeaders,omitempty"`
+	// Environment used for this request regardless of the globally selected one
+	EnvironmentOverride string `json:"environmentOverride,omitempty"`
+	// Assertions checked against each response, e.g. `$.status == "ok"`
+	Tests []string `json:"tests,omitempty"`
+	// Status codes the response should have; the indicator shows pass/fail when set
+	ExpectedStatus []int `json:"expectedStatus,omitempty"`
+	// Rules storing response values in variables, e.g. `token = header:X-Auth-Token`
+	Extract []string `json:"extract,omitempty"`
+	// Mapping lines for the Transform tab, e.g. `id = $.data.user.id`
+	Transform []string `json:"transform,omitempty"`
+	// Last JSONPath expression used on this request's responses
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Disabled requests stay in the list but are skipped by collection runs
+	Enabled bool `json:"enabled"`
+	// Gzip the body and send it with Content-Encoding: gzip
+	GzipBody bool `json:"gzipBody,omitempty"`
+	// Per-request client settings; a zero timeout uses the default timeout from Settings
+	TimeoutSeconds     int  `json:"timeoutSeconds,omitempty"`
+	FollowRedirects    bool `json:"followRedirects"`
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// Extra attempts when the request can't be sent or gets a 502, 503 or 504
+	Retries int `json:"retries,omitempty"`
+	// Proxy URL used for this request only, e.g. http://127.0.0.1:8080 for mitmproxy
+	Proxy string `json:"proxy,omitempty"`
+	// Sent as Accept-Language unless the headers set it, e.g. "fr-FR" for testing translations
+	AcceptLanguage string `json:"acceptLanguage,omitempty"`
+	// Body mode (see bodyModes); in file mode the body is streamed from BodyFile,
+	// chunked when ChunkedUpload is set
+	BodyMode      string `json:"bodyMode,omitempty"`
+	BodyFile      string `json:"bodyFile,omitempty"`
+	ChunkedUpload bool   `json:"chunkedUpload,omitempty"`
+	// Fields of a form body (multipart and URL-encoded modes)
+	FormFields []FormField `json:"formFields,omitempty"`
+	// Send If-None-Match/If-Modified-Since from the previous response and show its body on 304
+	ConditionalRequests bool `json:"conditionalRequests,omitempty"`
+	// Response view preferences: show the body unformatted / without line wrapping
+	RawResponse    bool `json:"rawResponse,omitempty"`
+	NoWrapResponse bool `json:"noWrapResponse,omitempty"`
 }
 
-type Collection struct {
-	Name     string       `json:"name"`
-	Requests []APIRequest `json:"requests"`
+// Requests saved before the Enabled/FollowRedirects flags existed must load with them on
+func (r *APIRequest) UnmarshalJSON(data []byte) error {
+	type plain APIRequest
+	decoded := plain{Enabled: true, FollowRedirects: true}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = APIRequest(decoded)
+	return nil
 }
 
-type Workspace struct {
-	Name        string       `json:"name"`
-	Collections []Collection `json:"collections"`
+// newAPIRequest returns a request with the default flags set, for importers
+func newAPIRequest(method, url string) APIRequest {
+	return APIRequest{
+		Name:            url,
+		Method:          method,
+		URL:             url,
+		Headers:         map[string]string{},
+		Enabled:         true,
+		FollowRedirects: true,
+	}
 }
 
-// Local storage helpers
-func getStoragePath() string {
-	dir, _ := os.UserHomeDir()
-	return filepath.Join(dir, ".postman-go-workspaces.json")
+type Collection struct {
+	Name string `json:"name"`
+	// Requests live in their own file (RequestsFile) and are read when the collection is
+	// first used; only collections saved before that split keep them inline
+	Requests     []APIRequest `json:"requests,omitempty"`
+	RequestsFile string       `json:"requestsFile,omitempty"`
+	loaded       bool
+	// Folders are stored with the requests; see folders.go
+	Folders []Folder `json:"folders,omitempty"`
+	// Environment selected automatically when switching to this collection
+	DefaultEnvironment string `json:"defaultEnvironment,omitempty"`
+	// Variables shared by the collection's requests; see resolveVariables for precedence
+	Variables map[string]string `json:"variables,omitempty"`
+	// Folders left open in the request tree, each by its name and its parents' names
+	OpenFolders [][]string `json:"openFolders,omitempty"`
 }
 
-func loadWorkspaces() ([]Workspace, error) {
-	path := getStoragePath()
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return []Workspace{}, nil
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// rebindEnvironment points collections bound to environment old at new ("" unbinds them)
+func (ws *Workspace) rebindEnvironment(old, new string) {
+	for i := range ws.Collections {
+		if ws.Collections[i].DefaultEnvironment == old {
+			ws.Collections[i].DefaultEnvironment = new
+		}
 	}
-	var workspaces []Workspace
-	err = json.Unmarshal(data, &workspaces)
-	return workspaces, err
 }
 
-func saveWorkspaces(workspaces []Workspace) error {
-	data, err := json.MarshalIndent(workspaces, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(getStoragePath(), data, 0644)
+type Workspace struct {
+	Name         string        `json:"name"`
+	Collections  []Collection  `json:"collections"`
+	Environments []Environment `json:"environments,omitempty"`
+	Flows        []Flow        `json:"flows,omitempty"`
+}
+
+// Lines starting with // or # are comments and are skipped
+func isHeaderComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#")
 }
 
 func parseHeaders(headerStr string) http.Header {
 	headers := http.Header{}
 	lines := strings.Split(headerStr, "\n")
 	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
+		if strings.TrimSpace(line) == "" || isHeaderComment(line) {
 			continue
 		}
 		parts := strings.SplitN(line, ":", 2)
@@ -87,6 +162,95 @@ func parseHeaders(headerStr string) http.Header {
 	return headers
 }
 
+// Read a body in chunks, reporting the running byte count after each read
+func readWithProgress(r io.Reader, onProgress func(read int64)) ([]byte, error) {
+	var buf bytes.Buffer
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			buf.Write(chunk[:n])
+			onProgress(int64(buf.Len()))
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return buf.Bytes(), err
+		}
+	}
+}
+
+// readLimited reads at most limit bytes with progress reporting; truncated is set when the body was longer
+func readLimited(r io.Reader, limit int64, onProgress func(read int64)) (data []byte, truncated bool, err error) {
+	data, err = readWithProgress(io.LimitReader(r, limit+1), onProgress)
+	if int64(len(data)) > limit {
+		return data[:limit], true, err
+	}
+	return data, false, err
+}
+
+// Message shown in place of a response body when the server sent none
+func emptyBodyMessage(method string, statusCode int, status string) string {
+	switch {
+	case statusCode == http.StatusNoContent:
+		return fmt.Sprintf("No content (%s): the server returned no body.", status)
+	case statusCode == http.StatusNotModified:
+		return fmt.Sprintf("Not modified (%s): the cached copy is still valid, so no body was sent.", status)
+	case method == "HEAD":
+		return fmt.Sprintf("No content (%s): HEAD responses never include a body.", status)
+	}
+	return fmt.Sprintf("No content (%s): the response body is empty.", status)
+}
+
+// isJSONContentType reports whether a Content-Type is JSON, including +json types such as application/problem+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Response body text for display, indented when pretty is set and the body is JSON
+func formatResponseBody(body []byte, pretty bool) string {
+	if pretty && json.Valid(body) {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, body, "", "    "); err == nil { // 4 spaces
+			return buf.String()
+		}
+	}
+	return string(body)
+}
+
+// Status indicator colors; the legend next to the status explains them
+var (
+	statusGreen  = color.NRGBA{0, 200, 0, 255}
+	statusYellow = color.NRGBA{200, 200, 0, 255}
+	statusRed    = color.NRGBA{200, 0, 0, 255}
+)
+
+const statusLegend = "Green: 2xx success    Yellow: 1xx / 3xx redirect / other    Red: 4xx client error, 5xx server error"
+
+// statusIndicator returns the indicator color and label for a status code
+func statusIndicator(code int) (color.NRGBA, string) {
+	switch {
+	case code >= 200 && code < 300:
+		return statusGreen, fmt.Sprintf("✅ %d OK", code)
+	case code == http.StatusNotModified:
+		return statusYellow, fmt.Sprintf("🟡 %d Not Modified", code)
+	case code >= 300 && code < 400:
+		return statusYellow, fmt.Sprintf("↪️ %d Redirect", code)
+	case code >= 400 && code < 500:
+		return statusRed, fmt.Sprintf("🔴 %d Client Error", code)
+	case code >= 500 && code < 600:
+		return statusRed, fmt.Sprintf("🔴 %d Server Error", code)
+	case code >= 100 && code < 200:
+		return statusYellow, fmt.Sprintf("🟡 %d Informational", code)
+	}
+	return statusYellow, fmt.Sprintf("🟡 %d Unknown", code)
+}
+
 // Format size in bytes, KB, or MB
 func formatSize(size int) string {
 	if size < 1024 {
@@ -98,7 +262,7 @@ func formatSize(size int) string {
 }
 
 func main() {
-	a := app.New()
+	a := app.NewWithID(appID)
 	w := a.NewWindow("codealchemyman)")
 
 	// HTTP method dropdown
@@ -107,14 +271,207 @@ func main() {
 	methodSelect.SetSelected("GET")
 
 	// URL entry
-	urlEntry := widget.NewEntry()
+	urlEntry := newShortcutEntry(false)
 	urlEntry.SetPlaceHolder("Enter request URL...")
 
 	// Headers and body
-	headersEntry := widget.NewMultiLineEntry()
-	headersEntry.SetPlaceHolder("Headers (key: value, one per line)")
-	bodyEntry := widget.NewMultiLineEntry()
+	headersEntry := newShortcutEntry(true)
+	headersEntry.SetPlaceHolder("Headers (key: value, one per line; prefix with // or # to disable)")
+	bodyEntry := newShortcutEntry(true)
 	bodyEntry.SetPlaceHolder("Request body (JSON, form, etc.)")
+	bodyModeSelect := widget.NewSelect(bodyModeLabels(), nil)
+	bodyModeSelect.SetSelected(bodyModeLabel(bodyModeRaw))
+	bodyFileEntry := widget.NewEntry()
+	bodyFileEntry.SetPlaceHolder("Path of the file to send as the body")
+	chunkedCheck := widget.NewCheck("Send chunked (Transfer-Encoding: chunked, no Content-Length)", nil)
+	// Fields of a form body, edited as rows in the Body tab
+	var formFields []FormField
+	var rebuildFormFieldRows func()
+	hostEntry := widget.NewEntry()
+	hostEntry.SetPlaceHolder("Host override (optional, e.g. api.example.com)")
+	envOverrideSelect := widget.NewSelect([]string{activeEnvironmentOption}, nil)
+	envOverrideSelect.SetSelected(activeEnvironmentOption)
+	testsEntry := widget.NewMultiLineEntry()
+	testsEntry.SetPlaceHolder("One assertion per line, e.g.\nstatus == 200\ntime < 500\n$.data.id exists\n$.items length > 0")
+	testsEntry.SetMinRowsVisible(5)
+	extractEntry := widget.NewMultiLineEntry()
+	extractEntry.SetPlaceHolder("One rule per line, e.g.\nuserId = $.data.id\ntoken = header:X-Auth-Token")
+	extractEntry.SetMinRowsVisible(5)
+	transformEntry := widget.NewMultiLineEntry()
+	transformEntry.SetPlaceHolder("One mapping per line, e.g.\nid = $.data.user.id\nemail = $.data.user.email")
+	transformEntry.SetMinRowsVisible(5)
+	jsonataEntry := widget.NewEntry()
+	jsonataEntry.SetPlaceHolder("Enter JSONata expression (e.g. $.foo.bar)")
+	gzipCheck := widget.NewCheck("Gzip request body (Content-Encoding: gzip)", nil)
+	authTypeSelect := widget.NewSelect(authTypeLabels(), nil)
+	authTypeSelect.SetSelected(authTypeLabel(authNone))
+	authTokenEntry := widget.NewPasswordEntry()
+	authTokenEntry.SetPlaceHolder("Token, e.g. eyJhbGciOi... or {{token}}")
+	authUsernameEntry := widget.NewEntry()
+	authUsernameEntry.SetPlaceHolder("Username")
+	authPasswordEntry := widget.NewPasswordEntry()
+	authPasswordEntry.SetPlaceHolder("Password")
+	languageEntry := widget.NewSelectEntry(commonLocales)
+	languageEntry.SetPlaceHolder("Accept-Language, e.g. fr-FR (empty = not sent)")
+	expectedStatusEntry := widget.NewEntry()
+	expectedStatusEntry.SetPlaceHolder("e.g. 200, 201 (empty = any)")
+	timeoutEntry := widget.NewEntry()
+	timeoutEntry.SetPlaceHolder("Seconds (empty = default timeout from Settings)")
+	retriesEntry := widget.NewEntry()
+	retriesEntry.SetPlaceHolder(fmt.Sprintf("Extra attempts on connection errors and 502/503/504 (0-%d)", maxRetries))
+	followRedirectsCheck := widget.NewCheck("Follow redirects", nil)
+	followRedirectsCheck.SetChecked(true)
+	insecureCheck := widget.NewCheck("Skip TLS certificate verification (insecure)", nil)
+	conditionalCheck := widget.NewCheck("Send If-None-Match / If-Modified-Since from the previous response", nil)
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetPlaceHolder("Proxy for this request only (e.g. http://127.0.0.1:8080, or direct for none)")
+	prettyCheck := widget.NewCheck("Pretty", nil)
+	prettyCheck.SetChecked(true)
+	wrapCheck := widget.NewCheck("Wrap", nil)
+	wrapCheck.SetChecked(true)
+
+	// The URL box shows the whole URL, or in parsed mode the URL without its query,
+	// which the Params tab then holds alone. Both are set up with the Params tab.
+	var fullURL func() string
+	var showURL func(rawURL string)
+
+	// Build a request definition from the form, and fill the form from one
+	formRequest := func() APIRequest {
+		headersMap := map[string]string{}
+		for k, v := range parseHeaders(headersEntry.Text) {
+			headersMap[k] = joinHeaderValues(v)
+		}
+		commented := []string{}
+		for _, line := range strings.Split(headersEntry.Text, "\n") {
+			if isHeaderComment(line) {
+				commented = append(commented, strings.TrimSpace(line))
+			}
+		}
+		envOverride := envOverrideSelect.Selected
+		if envOverride == activeEnvironmentOption {
+			envOverride = ""
+		}
+		timeoutSeconds, _ := strconv.Atoi(strings.TrimSpace(timeoutEntry.Text))
+		if timeoutSeconds < 0 {
+			timeoutSeconds = 0
+		}
+		retries, _ := strconv.Atoi(strings.TrimSpace(retriesEntry.Text))
+		retries = min(max(retries, 0), maxRetries)
+		tests := []string{}
+		for _, line := range strings.Split(testsEntry.Text, "\n") {
+			if strings.TrimSpace(line) != "" {
+				tests = append(tests, strings.TrimSpace(line))
+			}
+		}
+		transform := []string{}
+		for _, line := range strings.Split(transformEntry.Text, "\n") {
+			if strings.TrimSpace(line) != "" {
+				transform = append(transform, strings.TrimSpace(line))
+			}
+		}
+		extract := []string{}
+		for _, line := range strings.Split(extractEntry.Text, "\n") {
+			if strings.TrimSpace(line) != "" {
+				extract = append(extract, strings.TrimSpace(line))
+			}
+		}
+		var auth *RequestAuth
+		if authType := authTypeFromLabel(authTypeSelect.Selected); authType != authNone {
+			auth = &RequestAuth{Type: authType}
+			switch authType {
+			case authBearer:
+				auth.Token = strings.TrimSpace(authTokenEntry.Text)
+			case authBasic:
+				auth.Username = authUsernameEntry.Text
+				auth.Password = authPasswordEntry.Text
+			}
+		}
+		return APIRequest{
+			Name:    fullURL(),
+			Method:  methodSelect.Selected,
+			URL:     fullURL(),
+			Headers: headersMap,
+			Body:    bodyEntry.Text,
+			Host:    hostEntry.Text,
+			Auth:    auth,
+
+			BodyMode:            bodyModeFromLabel(bodyModeSelect.Selected),
+			BodyFile:            strings.TrimSpace(bodyFileEntry.Text),
+			ChunkedUpload:       chunkedCheck.Checked,
+			FormFields:          append([]FormField(nil), formFields...),
+			CommentedHeaders:    commented,
+			EnvironmentOverride: envOverride,
+			Tests:               tests,
+			ExpectedStatus:      parseStatusCodes(expectedStatusEntry.Text),
+			Extract:             extract,
+			Transform:           transform,
+			JSONPath:            jsonataEntry.Text,
+			Enabled:             true,
+			GzipBody:            gzipCheck.Checked,
+			TimeoutSeconds:      timeoutSeconds,
+			Retries:             retries,
+			FollowRedirects:     followRedirectsCheck.Checked,
+			InsecureSkipVerify:  insecureCheck.Checked,
+			Proxy:               strings.TrimSpace(proxyEntry.Text),
+			AcceptLanguage:      strings.TrimSpace(languageEntry.Text),
+			ConditionalRequests: conditionalCheck.Checked,
+			RawResponse:         !prettyCheck.Checked,
+			NoWrapResponse:      !wrapCheck.Checked,
+		}
+	}
+	// Set while the form is being filled programmatically so change handlers can ignore it
+	loadingForm := false
+	loadRequestIntoForm := func(r APIRequest) {
+		loadingForm = true
+		defer func() { loadingForm = false }()
+		methodSelect.SetSelected(r.Method)
+		showURL(r.URL)
+		headersText := headersToText(r.Headers)
+		for _, line := range r.CommentedHeaders {
+			headersText += line + "\n"
+		}
+		headersEntry.SetText(headersText)
+		bodyEntry.SetText(r.Body)
+		bodyModeSelect.SetSelected(bodyModeLabel(r.BodyMode))
+		bodyFileEntry.SetText(r.BodyFile)
+		chunkedCheck.SetChecked(r.ChunkedUpload)
+		formFields = append([]FormField(nil), r.FormFields...)
+		if rebuildFormFieldRows != nil {
+			rebuildFormFieldRows()
+		}
+		hostEntry.SetText(r.Host)
+		auth := RequestAuth{}
+		if r.Auth != nil {
+			auth = *r.Auth
+		}
+		authTypeSelect.SetSelected(authTypeLabel(auth.Type))
+		authTokenEntry.SetText(auth.Token)
+		authUsernameEntry.SetText(auth.Username)
+		authPasswordEntry.SetText(auth.Password)
+		envOverrideSelect.SetSelected(activeEnvironmentOption)
+		envOverrideSelect.SetSelected(r.EnvironmentOverride)
+		testsEntry.SetText(strings.Join(r.Tests, "\n"))
+		expectedStatusEntry.SetText(formatStatusCodes(r.ExpectedStatus))
+		transformEntry.SetText(strings.Join(r.Transform, "\n"))
+		extractEntry.SetText(strings.Join(r.Extract, "\n"))
+		jsonataEntry.SetText(r.JSONPath)
+		gzipCheck.SetChecked(r.GzipBody)
+		timeoutEntry.SetText("")
+		if r.TimeoutSeconds > 0 {
+			timeoutEntry.SetText(strconv.Itoa(r.TimeoutSeconds))
+		}
+		retriesEntry.SetText("")
+		if r.Retries > 0 {
+			retriesEntry.SetText(strconv.Itoa(r.Retries))
+		}
+		followRedirectsCheck.SetChecked(r.FollowRedirects)
+		insecureCheck.SetChecked(r.InsecureSkipVerify)
+		proxyEntry.SetText(r.Proxy)
+		languageEntry.SetText(r.AcceptLanguage)
+		conditionalCheck.SetChecked(r.ConditionalRequests)
+		prettyCheck.SetChecked(!r.RawResponse)
+		wrapCheck.SetChecked(!r.NoWrapResponse)
+	}
 
 	// Send button
 	sendBtn := widget.NewButton("Send", func() {})
@@ -125,7 +482,7 @@ func main() {
 	// jsonResponse.SetMinRowsVisible(60)
 	// jsonResponse.Wrapping = fyne.TextWrapBreak
 	// jsonResponse.Disable()
-	jsonResponse := widget.NewMultiLineEntry()
+	jsonResponse := newShortcutEntry(true)
 	jsonResponse.SetPlaceHolder("JSON response will appear here...")
 	jsonResponse.SetMinRowsVisible(30)
 	jsonResponse.Wrapping = fyne.TextWrapBreak // Keep for multi-line JSON
@@ -134,11 +491,58 @@ func main() {
 	jsonResponseScroller.SetMinSize(fyne.NewSize(1000, 600))
 
 	// Add response status, time, size display, and search/copy controls
-	responseMeta := widget.NewLabel("") // Will be set after each request
+	responseMeta := canvas.NewText("", theme.ForegroundColor()) // Will be set after each request
+	setResponseMeta := func(text string, level int) {
+		switch level {
+		case thresholdExceeded:
+			responseMeta.Color = color.NRGBA{230, 140, 0, 255} // Orange
+		case thresholdFarExceeded:
+			responseMeta.Color = color.NRGBA{200, 0, 0, 255} // Red
+		default:
+			responseMeta.Color = theme.ForegroundColor()
+		}
+		responseMeta.Text = text
+		responseMeta.Refresh()
+	}
 	statusColor := canvas.NewRectangle(&color.NRGBA{0, 0, 0, 255})
 	statusColor.SetMinSize(fyne.NewSize(18, 18))
 	responseStatus := widget.NewLabel("")
-	responseStatusContainer := container.NewHBox(statusColor, responseStatus, layout.NewSpacer(), responseMeta)
+	// Color legend for the status indicator, toggled by the help button; visibility is remembered
+	statusLegendLabel := widget.NewLabel(statusLegend)
+	if !a.Preferences().Bool(prefShowStatusLegend) {
+		statusLegendLabel.Hide()
+	}
+	statusLegendBtn := widget.NewButtonWithIcon("", theme.HelpIcon(), func() {
+		if statusLegendLabel.Visible() {
+			statusLegendLabel.Hide()
+		} else {
+			statusLegendLabel.Show()
+		}
+		a.Preferences().SetBool(prefShowStatusLegend, statusLegendLabel.Visible())
+	})
+	responseStatusContainer := container.NewVBox(
+		container.NewHBox(statusColor, responseStatus, statusLegendBtn, layout.NewSpacer(), responseMeta),
+		statusLegendLabel,
+	)
+
+	// Download progress, shown while a response body streams in. The send goroutine
+	// counts the bytes while the progress bar's refreshes read them.
+	var downloadedBytes atomic.Int64
+	// When the running request has a timeout, the time it will be aborted at in Unix
+	// nanoseconds, 0 otherwise; the countdown ticker reads it off the send goroutine
+	var sendDeadline atomic.Int64
+	downloadProgress := widget.NewProgressBar()
+	downloadProgress.TextFormatter = func() string {
+		text := formatSize(int(downloadedBytes.Load()))
+		if downloadProgress.Value > 0 {
+			text = fmt.Sprintf("%.0f%%  (%s)", downloadProgress.Value*100, text)
+		}
+		if deadline := sendDeadline.Load(); deadline != 0 {
+			text += fmt.Sprintf("    ⏱ times out in %ds", int(math.Ceil(time.Until(time.Unix(0, deadline)).Seconds())))
+		}
+		return text
+	}
+	downloadProgress.Hide()
 
 	// Enhanced search functionality with highlighting and dynamic sizing
 	searchEntry := widget.NewEntry()
@@ -152,15 +556,12 @@ func main() {
 	nextIcon := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), nil)
 	clearIcon := widget.NewButtonWithIcon("", theme.CancelIcon(), nil)
 
-	// Variables to track search state
-	var currentSearchQuery string
-	var searchResults []int // Store all match positions
-	var currentMatchIndex int = -1
-	var originalText string // Store original text without highlighting
+	// Search state; search.text is the response as received, the box may show it highlighted
+	search := newResponseSearch()
 
 	// Update button states and match count
 	updateSearchNav := func() {
-		if len(searchResults) > 0 && currentMatchIndex >= 0 {
+		if search.current >= 0 {
 			prevIcon.Enable()
 			nextIcon.Enable()
 			clearIcon.Enable()
@@ -171,159 +572,151 @@ func main() {
 		}
 	}
 
-	// Highlight function (same as before, but always highlights all matches, and current is styled differently)
-	highlightText := func(text, query string, currentIdx int) string {
-		if query == "" {
-			return text
-		}
-		searchResults = []int{}
-		lowerText := strings.ToLower(text)
-		lowerQuery := strings.ToLower(query)
-		startPos := 0
-		for {
-			idx := strings.Index(lowerText[startPos:], lowerQuery)
-			if idx == -1 {
-				break
-			}
-			absoluteIdx := startPos + idx
-			searchResults = append(searchResults, absoluteIdx)
-			startPos = absoluteIdx + len(query)
-		}
-		if len(searchResults) == 0 {
-			return text
-		}
-		result := text
-		offset := 0
-		for i, matchPos := range searchResults {
-			start := matchPos + offset
-			end := start + len(query)
-			if end <= len(result) {
-				var highlighted string
-				if i == currentIdx {
-					highlighted = "【" + result[start:end] + "】" // Current match
-				} else {
-					highlighted = "〔" + result[start:end] + "〕" // Other matches
-				}
-				result = result[:start] + highlighted + result[end:]
-				offset += len(highlighted) - len(query)
-			}
-		}
-		return result
+	// Show new response text, dropping any search over the previous one
+	showResponseText := func(text string) {
+		search.setText(text)
+		jsonResponse.SetText(text)
+		updateSearchNav()
 	}
 
-	// Navigation logic
-	navigateToMatch := func(matchIndex int) {
-		if len(searchResults) == 0 || matchIndex < 0 || matchIndex >= len(searchResults) {
-			return
-		}
-		currentMatchIndex = matchIndex
-		if originalText != "" && currentSearchQuery != "" {
-			highlightedText := highlightText(originalText, currentSearchQuery, currentMatchIndex)
-			jsonResponse.SetText(highlightedText)
-		}
-		// Scroll to match (approximate)
-		matchPos := searchResults[matchIndex]
-		text := originalText
-		if text == "" {
-			text = jsonResponse.Text
-		}
-		before := text[:matchPos]
-		row := strings.Count(before, "\n")
-		jsonResponse.CursorRow = row
+	// Redraw the highlights and scroll to the current match (approximate)
+	showMatch := func() {
+		jsonResponse.SetText(search.highlighted())
+		jsonResponse.CursorRow = search.currentRow()
 		jsonResponse.CursorColumn = 0
 		jsonResponse.Refresh()
 		updateSearchNav()
 	}
 
+	// Query options, and the reason an invalid pattern was rejected
+	regexCheck := widget.NewCheck(".*", nil)
+	caseCheck := widget.NewCheck("Aa", nil)
+	searchError := widget.NewLabel("")
+	searchError.Importance = widget.DangerImportance
+	searchError.Hide()
+	searchOpts := func() searchOptions {
+		return searchOptions{Regex: regexCheck.Checked, CaseSensitive: caseCheck.Checked}
+	}
+
 	// Search action
 	searchAction := func() {
 		query := strings.TrimSpace(searchEntry.Text)
-		if query == "" {
-			if originalText != "" {
-				jsonResponse.SetText(originalText)
-				originalText = ""
-			}
-			currentSearchQuery = ""
-			searchResults = []int{}
-			currentMatchIndex = -1
-			updateSearchNav()
-			return
-		}
-		if currentSearchQuery != query {
-			if originalText == "" {
-				originalText = jsonResponse.Text
-			}
-			currentSearchQuery = query
-			_ = highlightText(originalText, query, 0) // update searchResults
-			if len(searchResults) > 0 {
-				currentMatchIndex = 0
-				navigateToMatch(0)
-			} else {
-				jsonResponse.SetText(originalText)
-				currentMatchIndex = -1
-			}
-			updateSearchNav()
-		} else if len(searchResults) > 0 {
+		if search.searched(query, searchOpts()) && search.current >= 0 {
 			// Next match
-			nextIdx := (currentMatchIndex + 1) % len(searchResults)
-			navigateToMatch(nextIdx)
+			search.move(1)
+			showMatch()
+			return
+		}
+		if _, err := search.find(query, searchOpts()); err != nil {
+			// Keep the previous matches while the pattern is being fixed
+			searchError.SetText(err.Error())
+			searchError.Show()
+			return
 		}
+		searchError.Hide()
+		showMatch()
 	}
+	// Changing an option searches again with the same query
+	regexCheck.OnChanged = func(checked bool) {
+		a.Preferences().SetBool(prefSearchRegex, checked)
+		if strings.TrimSpace(searchEntry.Text) != "" {
+			searchAction()
+		}
+	}
+	caseCheck.OnChanged = func(checked bool) {
+		a.Preferences().SetBool(prefSearchCaseSensitive, checked)
+		if strings.TrimSpace(searchEntry.Text) != "" {
+			searchAction()
+		}
+	}
+	regexCheck.SetChecked(a.Preferences().Bool(prefSearchRegex))
+	caseCheck.SetChecked(a.Preferences().Bool(prefSearchCaseSensitive))
 
 	// Icon button callbacks
 	searchIcon.OnTapped = searchAction
 	searchEntry.OnSubmitted = func(_ string) { searchAction() }
 	prevIcon.OnTapped = func() {
-		if len(searchResults) == 0 {
-			return
-		}
-		prevIdx := currentMatchIndex - 1
-		if prevIdx < 0 {
-			prevIdx = len(searchResults) - 1
-		}
-		navigateToMatch(prevIdx)
+		search.move(-1)
+		showMatch()
 	}
 	nextIcon.OnTapped = func() {
-		if len(searchResults) == 0 {
-			return
-		}
-		nextIdx := (currentMatchIndex + 1) % len(searchResults)
-		navigateToMatch(nextIdx)
+		search.move(1)
+		showMatch()
 	}
 	clearIcon.OnTapped = func() {
 		searchEntry.SetText("")
-		if originalText != "" {
-			jsonResponse.SetText(originalText)
-			originalText = ""
-		}
-		currentSearchQuery = ""
-		searchResults = []int{}
-		currentMatchIndex = -1
-		updateSearchNav()
+		searchError.Hide()
+		search.clear()
+		showMatch()
 	}
 	copyIcon.OnTapped = func() {
-		textToCopy := originalText
-		if textToCopy == "" {
-			textToCopy = jsonResponse.Text
-		}
-		w.Clipboard().SetContent(textToCopy)
+		// Copy the response itself, never the highlighted text in the box
+		w.Clipboard().SetContent(search.text)
 		dialog.ShowInformation("Copied", "Response copied to clipboard!", w)
 	}
 
+	// Find-as-you-type: re-run the search shortly after typing stops
+	liveSearchCheck := widget.NewCheck("Live", func(checked bool) {
+		a.Preferences().SetBool(prefLiveSearch, checked)
+	})
+	liveSearchCheck.SetChecked(a.Preferences().Bool(prefLiveSearch))
+	var liveSearchTimer *time.Timer
+	searchEntry.OnChanged = func(string) {
+		if !liveSearchCheck.Checked {
+			return
+		}
+		if liveSearchTimer != nil {
+			liveSearchTimer.Stop()
+		}
+		liveSearchTimer = time.AfterFunc(liveSearchDelay, func() {
+			// Only search when the query changed, otherwise searchAction would step to the next match
+			if !search.searched(strings.TrimSpace(searchEntry.Text), searchOpts()) {
+				searchAction()
+			}
+		})
+	}
+
+	// Pop the current response out into its own window (a frozen snapshot)
+	popOutIcon := widget.NewButtonWithIcon("", theme.ViewFullScreenIcon(), func() {
+		text := search.text
+		title := "Response"
+		if responseStatus.Text != "" {
+			title += " - " + responseStatus.Text
+		}
+		snapshot := widget.NewMultiLineEntry()
+		snapshot.Wrapping = fyne.TextWrapBreak
+		snapshot.SetText(text)
+		popOut := a.NewWindow(title)
+		popOut.SetContent(container.NewBorder(
+			widget.NewLabel(time.Now().Format("Snapshot taken 15:04:05")), nil, nil, nil,
+			container.NewVScroll(snapshot),
+		))
+		popOut.Resize(fyne.NewSize(1200, 900))
+		popOut.Show()
+	})
+
 	// Overlay search bar styled like Postman (floating, top right)
-	searchBarOverlay := container.NewHBox(
+	pinIcon := widget.NewButtonWithIcon("", theme.VisibilityIcon(), nil)
+	searchBarOverlay := container.NewVBox(
 		container.NewHBox(
 			searchEntry,
+			liveSearchCheck,
+			regexCheck,
+			caseCheck,
 			searchIcon,
 			prevIcon,
 			nextIcon,
 			clearIcon,
 			copyIcon,
+			popOutIcon,
+			pinIcon,
 		),
+		searchError,
 	)
+	overlayHolder := container.NewHBox(layout.NewSpacer(), searchBarOverlay)
 	searchBarOverlayBG := container.NewVBox(
 		canvas.NewRectangle(color.NRGBA{240, 240, 240, 220}),
-		container.NewHBox(layout.NewSpacer(), searchBarOverlay),
+		overlayHolder,
 	)
 	searchBarOverlayBG.Objects[0].Resize(fyne.NewSize(420, 44)) // Set overlay background size
 
@@ -335,476 +728,3561 @@ func main() {
 		),
 	)
 
+	// Pinning moves the search bar out of the floating overlay into a fixed row above the response
+	pinnedSearchRow := container.NewHBox(layout.NewSpacer())
+	pinnedSearchRow.Hide()
+	setSearchPinned := func(pinned bool) {
+		a.Preferences().SetBool(prefSearchPinned, pinned)
+		if pinned {
+			overlayHolder.Remove(searchBarOverlay)
+			pinnedSearchRow.Add(searchBarOverlay)
+			searchBarOverlayBG.Hide()
+			pinnedSearchRow.Show()
+			pinIcon.Importance = widget.HighImportance
+		} else {
+			pinnedSearchRow.Remove(searchBarOverlay)
+			overlayHolder.Add(searchBarOverlay)
+			pinnedSearchRow.Hide()
+			searchBarOverlayBG.Show()
+			pinIcon.Importance = widget.MediumImportance
+		}
+		pinIcon.Refresh()
+	}
+	pinIcon.OnTapped = func() {
+		setSearchPinned(!a.Preferences().Bool(prefSearchPinned))
+	}
+	if a.Preferences().Bool(prefSearchPinned) {
+		setSearchPinned(true)
+	}
+
 	// Add response status and headers display
 	// statusLabel := widget.NewLabel("")
 	headersBox := widget.NewMultiLineEntry()
 	headersBox.SetPlaceHolder("Response headers will appear here...")
+	// Response headers exactly as received, for servers whose header casing or order matters
+	captureRawHeadersCheck := widget.NewCheck("Capture raw headers (sends over HTTP/1.1)", func(checked bool) {
+		a.Preferences().SetBool(prefCaptureRawHeaders, checked)
+	})
+	captureRawHeadersCheck.SetChecked(a.Preferences().Bool(prefCaptureRawHeaders))
+	rawHeadersBox := widget.NewMultiLineEntry()
+	rawHeadersBox.SetPlaceHolder("Raw response headers will appear here when capture is on...")
+	rawHeadersBox.SetMinRowsVisible(20)
 
 	// UI for workspaces/collections
 	workspaces, _ := loadWorkspaces()
+	activity := &activityLog{}
 
 	// Track selected collection index
 	var selectedCollectionIdx int = -1
+	// Index of the saved request loaded into the form, -1 for an unsaved request
+	var selectedRequestIdx int = -1
+
+	// Sends, flows and collection runs resolve and store variables off the UI goroutine;
+	// variablesMu guards environment, collection and scratch variables against them
+	var variablesMu sync.Mutex
+
+	// Saving snapshots the model where it is changed and writes the snapshot. Writes are
+	// numbered so a delayed auto-save never overwrites a newer save.
+	var saveMu sync.Mutex
+	snapshots, written := 0, 0
+	// Must be called with variablesMu held
+	snapshotModel := func() (*workspacesSnapshot, int, error) {
+		saveMu.Lock()
+		defer saveMu.Unlock()
+		snapshot, err := snapshotWorkspaces(workspaces)
+		snapshots++
+		return snapshot, snapshots, err
+	}
+	writeSnapshot := func(snapshot *workspacesSnapshot, n int) error {
+		saveMu.Lock()
+		defer saveMu.Unlock()
+		if n < written {
+			return nil
+		}
+		written = n
+		return snapshot.write()
+	}
+	// Must be called with variablesMu held
+	saveModelLocked := func() error {
+		snapshot, n, err := snapshotModel()
+		if err != nil {
+			return err
+		}
+		return writeSnapshot(snapshot, n)
+	}
+
+	// Debounced auto-save with a visible status; every save, debounced or not, updates it
+	saveIndicator := widget.NewLabel("")
+	var autoSaveTimer *time.Timer
+	showSaved := func(err error) {
+		if err != nil {
+			saveIndicator.SetText("Save failed")
+			activity.add("Saving workspaces failed: %v", err)
+			return
+		}
+		saveIndicator.SetText("Saved " + time.Now().Format("15:04:05"))
+	}
+	scheduleAutoSave := func() {
+		saveIndicator.SetText("Unsaved changes...")
+		if autoSaveTimer != nil {
+			autoSaveTimer.Stop()
+		}
+		variablesMu.Lock()
+		snapshot, n, err := snapshotModel()
+		variablesMu.Unlock()
+		if err != nil {
+			showSaved(err)
+			return
+		}
+		autoSaveTimer = time.AfterFunc(autoSaveDelay, func() {
+			err := writeSnapshot(snapshot, n)
+			showSaved(err)
+			if err == nil {
+				activity.add("Auto-saved workspaces")
+			}
+		})
+	}
+	// saveModel saves at once, replacing any pending auto-save
+	saveModel := func() error {
+		if autoSaveTimer != nil {
+			autoSaveTimer.Stop()
+		}
+		variablesMu.Lock()
+		err := saveModelLocked()
+		variablesMu.Unlock()
+		showSaved(err)
+		return err
+	}
 
 	// Forward declare UI elements that will be referenced in functions
+	var responseTabs *container.AppTabs
 	var workspaceSelect *widget.Select
 	var collectionSelect *widget.Select
-	var requestList *widget.List
+	var requestTree *widget.Tree
+	var restoreOpenFolders func()
+	var envSelect *widget.Select
+	var refreshFlowOptions func()
 
-	// Workspace management functions
-	createNewWorkspace := func() {
-		entry := widget.NewEntry()
-		form := dialog.NewForm("New Workspace", "Create", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("Workspace Name", entry),
-		}, func(ok bool) {
-			if !ok || entry.Text == "" {
-				return
+	currentWorkspaceIdx := func() int {
+		for i, ws := range workspaces {
+			if ws.Name == workspaceSelect.Selected {
+				return i
 			}
-			workspaces = append(workspaces, Workspace{Name: entry.Text, Collections: []Collection{}})
-			err := saveWorkspaces(workspaces)
-			if err == nil {
-				workspaceNames := []string{"+ New Workspace"}
-				for _, ws := range workspaces {
-					workspaceNames = append(workspaceNames, ws.Name)
-				}
-				workspaceSelect.Options = workspaceNames
-				workspaceSelect.SetSelected(entry.Text)
-				collectionSelect.Options = []string{"+ New Collection"}
-				collectionSelect.SetSelected("")
-				requestList.Refresh()
+		}
+		return -1
+	}
+
+	// Cookies set by responses, kept per workspace
+	cookies := loadCookieJars(a.Preferences().Bool(prefPersistCookies))
+	currentCookieJar := func() http.CookieJar {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			return nil
+		}
+		return cookies.forWorkspace(workspaces[wsIdx].Name)
+	}
+
+	// Environment helpers
+	refreshEnvironmentOptions := func() {
+		envOptions := []string{noEnvironment}
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 {
+			for _, env := range workspaces[wsIdx].Environments {
+				envOptions = append(envOptions, env.Name)
 			}
-		}, w)
-		form.Show()
+		}
+		envSelect.Options = envOptions
+		selected := noEnvironment
+		for _, opt := range envOptions {
+			if opt == envSelect.Selected {
+				selected = opt
+			}
+		}
+		envSelect.SetSelected(selected)
+		envSelect.Refresh()
+		envOverrideSelect.Options = append([]string{activeEnvironmentOption}, envOptions[1:]...)
+		envOverrideSelect.Refresh()
 	}
 
-	// Collection management functions
-	createNewCollection := func() {
-		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" {
-			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
-			return
+	environmentVariables := func(name string) map[string]string {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			return map[string]string{}
 		}
-		entry := widget.NewEntry()
-		form := dialog.NewForm("New Collection", "Create", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("Collection Name", entry),
-		}, func(ok bool) {
-			if !ok || entry.Text == "" {
-				return
+		for _, env := range workspaces[wsIdx].Environments {
+			if env.Name == name {
+				return env.Variables
 			}
-			for i, ws := range workspaces {
-				if ws.Name == workspaceSelect.Selected {
-					workspaces[i].Collections = append(workspaces[i].Collections, Collection{Name: entry.Text})
-					err := saveWorkspaces(workspaces)
-					if err == nil {
-						// Update collection dropdown options
-						collectionOptions := []string{"+ New Collection"}
-						for _, col := range workspaces[i].Collections {
-							collectionOptions = append(collectionOptions, col.Name)
-						}
-						collectionSelect.Options = collectionOptions
-						collectionSelect.SetSelected(entry.Text)
-						selectedCollectionIdx = len(workspaces[i].Collections) - 1
-						requestList.Refresh()
-					}
+		}
+		return map[string]string{}
+	}
+
+	// Scratch variables for quick experiments; they override every other scope and only
+	// outlive the session when the user chooses to keep them
+	scratchVariables := map[string]string{}
+	if a.Preferences().Bool(prefKeepScratch) {
+		scratchVariables = parseVariables(a.Preferences().String(prefScratchVariables))
+	}
+
+	// Global, collection and environment variables merged in the configured precedence, with scratch
+	// variables on top. collection names the collection r belongs to, "" for none. A request's
+	// environment override wins over the globally selected environment.
+	variablesFor := func(r APIRequest, collection string) map[string]string {
+		variablesMu.Lock()
+		defer variablesMu.Unlock()
+		envName := envSelect.Selected
+		if r.EnvironmentOverride != "" {
+			envName = r.EnvironmentOverride
+		}
+		collectionVars := map[string]string{}
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 && collection != "" {
+			for _, c := range workspaces[wsIdx].Collections {
+				if c.Name == collection {
+					collectionVars = c.Variables
 				}
 			}
-		}, w)
-		form.Show()
+		}
+		vars := resolveVariables(parseVariablePrecedence(a.Preferences().String(prefVariablePrecedence)), map[string]map[string]string{
+			scopeEnvironment: environmentVariables(envName),
+			scopeCollection:  collectionVars,
+			scopeGlobal:      parseVariables(a.Preferences().String(prefGlobalVariables)),
+		})
+		for k, v := range scratchVariables {
+			vars[k] = v
+		}
+		return vars
 	}
 
-	// Request management functions
-	editRequestName := func(reqIdx int) {
-		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" || selectedCollectionIdx < 0 {
-			return
+	// Store extracted values in the request's environment, or in the scratchpad when there is none
+	storeVariables := func(r APIRequest, values map[string]string) (string, error) {
+		variablesMu.Lock()
+		defer variablesMu.Unlock()
+		envName := envSelect.Selected
+		if r.EnvironmentOverride != "" {
+			envName = r.EnvironmentOverride
 		}
-		wsIdx := -1
-		for i, ws := range workspaces {
-			if ws.Name == workspaceSelect.Selected {
-				wsIdx = i
-				break
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 && envName != noEnvironment {
+			for i := range workspaces[wsIdx].Environments {
+				env := &workspaces[wsIdx].Environments[i]
+				if env.Name != envName {
+					continue
+				}
+				if env.Variables == nil {
+					env.Variables = map[string]string{}
+				}
+				for k, v := range values {
+					env.Variables[k] = v
+				}
+				if err := saveModelLocked(); err != nil {
+					return envName, fmt.Errorf("saving environment %q failed: %v", envName, err)
+				}
+				return envName, nil
 			}
 		}
-		if wsIdx == -1 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
-			return
+		for k, v := range values {
+			scratchVariables[k] = v
 		}
-
-		coll := &workspaces[wsIdx].Collections[selectedCollectionIdx]
-		if reqIdx >= len(coll.Requests) {
-			return
+		if a.Preferences().Bool(prefKeepScratch) {
+			a.Preferences().SetString(prefScratchVariables, formatVariables(scratchVariables))
 		}
+		return "scratchpad", nil
+	}
 
-		entry := widget.NewEntry()
-		entry.SetText(coll.Requests[reqIdx].Name)
-		form := dialog.NewForm("Edit Request Name", "Save", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("Request Name", entry),
-		}, func(ok bool) {
-			if !ok || entry.Text == "" {
+	showScratchpad := func() {
+		varsEntry := widget.NewMultiLineEntry()
+		varsEntry.SetPlaceHolder("KEY=VALUE, one per line, e.g. token=eyJhbGciOi...")
+		varsEntry.SetMinRowsVisible(8)
+		variablesMu.Lock()
+		varsEntry.SetText(formatVariables(scratchVariables))
+		variablesMu.Unlock()
+		keepCheck := widget.NewCheck("Keep after closing the app", nil)
+		keepCheck.SetChecked(a.Preferences().Bool(prefKeepScratch))
+		content := container.NewBorder(
+			widget.NewLabel("Session variables that override every environment, collection and global value."),
+			keepCheck, nil, nil, varsEntry,
+		)
+		d := dialog.NewCustomConfirm("Scratchpad", "Save", "Cancel", content, func(ok bool) {
+			if !ok {
 				return
 			}
-			coll.Requests[reqIdx].Name = entry.Text
-			err := saveWorkspaces(workspaces)
-			if err == nil {
-				requestList.Refresh()
+			variablesMu.Lock()
+			defer variablesMu.Unlock()
+			scratchVariables = parseVariables(varsEntry.Text)
+			a.Preferences().SetBool(prefKeepScratch, keepCheck.Checked)
+			if keepCheck.Checked {
+				a.Preferences().SetString(prefScratchVariables, formatVariables(scratchVariables))
+			} else {
+				a.Preferences().RemoveValue(prefScratchVariables)
 			}
 		}, w)
-		form.Show()
+		d.Resize(fyne.NewSize(550, 380))
+		d.Show()
 	}
 
-	deleteRequest := func(reqIdx int) {
-		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" || selectedCollectionIdx < 0 {
+	// Cookies stored for the current workspace, each of which can be deleted
+	showCookies := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace to see its cookies.", w)
 			return
 		}
-		wsIdx := -1
-		for i, ws := range workspaces {
+		jar := cookies.forWorkspace(workspaces[wsIdx].Name)
+		stored := jar.list()
+		summary := widget.NewLabel("")
+		var list *widget.List
+		refresh := func() {
+			stored = jar.list()
+			summary.SetText(fmt.Sprintf("%d cookie(s) for workspace %s", len(stored), workspaces[wsIdx].Name))
+			list.Refresh()
+			_ = cookies.save()
+		}
+		list = widget.NewList(
+			func() int { return len(stored) },
+			func() fyne.CanvasObject {
+				return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel(""))
+			},
+			func(id widget.ListItemID, obj fyne.CanvasObject) {
+				row := obj.(*fyne.Container)
+				c := stored[id]
+				expires := "session"
+				if !c.Expires.IsZero() {
+					expires = "expires " + c.Expires.Local().Format("2006-01-02 15:04")
+				}
+				domain := c.Domain
+				if !c.HostOnly {
+					domain = "." + domain
+				}
+				row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s%s  %s=%s  (%s)", domain, c.Path, c.Name, c.Value, expires))
+				row.Objects[1].(*widget.Button).OnTapped = func() {
+					jar.remove(c)
+					refresh()
+				}
+			},
+		)
+		clearBtn := widget.NewButtonWithIcon("Delete All", theme.DeleteIcon(), func() {
+			jar.clear()
+			refresh()
+		})
+		persistCheck := widget.NewCheck("Keep cookies after closing the app", func(checked bool) {
+			a.Preferences().SetBool(prefPersistCookies, checked)
+			if err := cookies.setPersist(checked); err != nil {
+				dialog.ShowError(err, w)
+			}
+		})
+		persistCheck.SetChecked(a.Preferences().Bool(prefPersistCookies))
+		refresh()
+		d := dialog.NewCustom("Cookies", "Close", container.NewBorder(
+			summary, container.NewHBox(persistCheck, layout.NewSpacer(), clearBtn), nil, nil, list,
+		), w)
+		d.Resize(fyne.NewSize(750, 450))
+		d.Show()
+	}
+
+	// Shows which environment the selected collection is bound to
+	collectionEnvLabel := widget.NewLabel("")
+	collectionEnvLabel.Hide()
+	updateCollectionEnvLabel := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			collectionEnvLabel.Hide()
+			return
+		}
+		if env := workspaces[wsIdx].Collections[selectedCollectionIdx].DefaultEnvironment; env != "" {
+			collectionEnvLabel.SetText("Default environment: " + env)
+			collectionEnvLabel.Show()
+		} else {
+			collectionEnvLabel.Hide()
+		}
+	}
+	// Two environments' variables side by side; rows that differ or exist on one side only are highlighted
+	showCompareEnvironments := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || len(workspaces[wsIdx].Environments) < 2 {
+			dialog.ShowInformation("Compare", "The workspace needs at least two environments.", w)
+			return
+		}
+		names := []string{}
+		for _, env := range workspaces[wsIdx].Environments {
+			names = append(names, env.Name)
+		}
+		var rows []variableDiff
+		leftSelect := widget.NewSelect(names, nil)
+		rightSelect := widget.NewSelect(names, nil)
+		onlyDiffs := widget.NewCheck("Only differences", nil)
+		summary := widget.NewLabel("")
+		table := widget.NewTable(
+			func() (int, int) { return len(rows) + 1, 3 },
+			func() fyne.CanvasObject { return canvas.NewText("", theme.ForegroundColor()) },
+			func(id widget.TableCellID, obj fyne.CanvasObject) {
+				text := obj.(*canvas.Text)
+				text.TextStyle = fyne.TextStyle{}
+				text.Color = theme.ForegroundColor()
+				if id.Row == 0 {
+					text.Text = []string{"Variable", leftSelect.Selected, rightSelect.Selected}[id.Col]
+					text.TextStyle.Bold = true
+					text.Refresh()
+					return
+				}
+				row := rows[id.Row-1]
+				value := func(v string, ok bool) string {
+					if !ok {
+						return "(missing)"
+					}
+					return v
+				}
+				text.Text = []string{row.Name, value(row.Left, row.InLeft), value(row.Right, row.InRight)}[id.Col]
+				if row.differs() {
+					text.Color = theme.ErrorColor()
+				}
+				text.Refresh()
+			},
+		)
+		table.SetColumnWidth(0, 220)
+		table.SetColumnWidth(1, 330)
+		table.SetColumnWidth(2, 330)
+		variablesOf := func(name string) map[string]string {
+			variablesMu.Lock()
+			defer variablesMu.Unlock()
+			vars := map[string]string{}
+			for _, env := range workspaces[wsIdx].Environments {
+				if env.Name == name {
+					for k, v := range env.Variables {
+						vars[k] = v
+					}
+				}
+			}
+			return vars
+		}
+		update := func() {
+			all := diffVariables(variablesOf(leftSelect.Selected), variablesOf(rightSelect.Selected))
+			rows = rows[:0]
+			different := 0
+			for _, d := range all {
+				if d.differs() {
+					different++
+				}
+				if !onlyDiffs.Checked || d.differs() {
+					rows = append(rows, d)
+				}
+			}
+			summary.SetText(fmt.Sprintf("%d variables, %d different", len(all), different))
+			table.Refresh()
+		}
+		leftSelect.OnChanged = func(string) { update() }
+		rightSelect.OnChanged = func(string) { update() }
+		onlyDiffs.OnChanged = func(bool) { update() }
+		leftSelect.SetSelected(names[0])
+		rightSelect.SetSelected(names[1])
+		content := container.NewBorder(
+			container.NewVBox(container.NewGridWithColumns(2, leftSelect, rightSelect), container.NewHBox(onlyDiffs, layout.NewSpacer(), summary)),
+			nil, nil, nil,
+			table,
+		)
+		d := dialog.NewCustom("Compare Environments", "Close", content, w)
+		d.Resize(fyne.NewSize(950, 600))
+		d.Show()
+	}
+
+	showEnvironmentManager := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		const newEnvOption = "+ New Environment"
+		envNames := func() []string {
+			names := []string{newEnvOption}
+			for _, env := range workspaces[wsIdx].Environments {
+				names = append(names, env.Name)
+			}
+			return names
+		}
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("Environment name")
+		varsEntry := widget.NewMultiLineEntry()
+		varsEntry.SetPlaceHolder("KEY=VALUE, one per line (use as {{KEY}})")
+		varsEntry.SetMinRowsVisible(12)
+		pick := widget.NewSelect(envNames(), func(selected string) {
+			nameEntry.SetText("")
+			varsEntry.SetText("")
+			variablesMu.Lock()
+			defer variablesMu.Unlock()
+			for _, env := range workspaces[wsIdx].Environments {
+				if env.Name == selected {
+					nameEntry.SetText(env.Name)
+					varsEntry.SetText(formatVariables(env.Variables))
+				}
+			}
+		})
+		saveBtn := widget.NewButton("Save Environment", func() {
+			name := strings.TrimSpace(nameEntry.Text)
+			if name == "" {
+				dialog.ShowInformation("No Name", "Please enter an environment name.", w)
+				return
+			}
+			// Saving under the name of another environment would leave two with that name
+			for _, e := range workspaces[wsIdx].Environments {
+				if e.Name == name && e.Name != pick.Selected {
+					dialog.ShowError(fmt.Errorf("an environment named '%s' already exists", name), w)
+					return
+				}
+			}
+			env := Environment{Name: name, Variables: parseVariables(varsEntry.Text)}
+			variablesMu.Lock()
+			envs := workspaces[wsIdx].Environments
+			replaced := false
+			for i := range envs {
+				if envs[i].Name == pick.Selected {
+					workspaces[wsIdx].rebindEnvironment(envs[i].Name, name)
+					envs[i] = env
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				envs = append(envs, env)
+			}
+			workspaces[wsIdx].Environments = envs
+			variablesMu.Unlock()
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			pick.Options = envNames()
+			pick.SetSelected(name)
+			refreshEnvironmentOptions()
+			updateCollectionEnvLabel()
+		})
+		deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			envs := workspaces[wsIdx].Environments
+			for i := range envs {
+				if envs[i].Name == pick.Selected {
+					variablesMu.Lock()
+					workspaces[wsIdx].rebindEnvironment(envs[i].Name, "")
+					workspaces[wsIdx].Environments = append(envs[:i], envs[i+1:]...)
+					variablesMu.Unlock()
+					_ = saveModel()
+					pick.Options = envNames()
+					pick.SetSelected(newEnvOption)
+					refreshEnvironmentOptions()
+					updateCollectionEnvLabel()
+					return
+				}
+			}
+		})
+		// Clone the picked environment under a new name, copying its variables
+		duplicateBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+			for _, env := range workspaces[wsIdx].Environments {
+				if env.Name != pick.Selected {
+					continue
+				}
+				taken := map[string]bool{}
+				for _, e := range workspaces[wsIdx].Environments {
+					taken[e.Name] = true
+				}
+				name := env.Name + " copy"
+				for n := 2; taken[name]; n++ {
+					name = fmt.Sprintf("%s copy %d", env.Name, n)
+				}
+				variablesMu.Lock()
+				vars := make(map[string]string, len(env.Variables))
+				for k, v := range env.Variables {
+					vars[k] = v
+				}
+				workspaces[wsIdx].Environments = append(workspaces[wsIdx].Environments, Environment{Name: name, Variables: vars})
+				variablesMu.Unlock()
+				if err := saveModel(); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				pick.Options = envNames()
+				pick.SetSelected(name)
+				refreshEnvironmentOptions()
+				return
+			}
+		})
+		pick.SetSelected(newEnvOption)
+		if envSelect.Selected != noEnvironment {
+			pick.SetSelected(envSelect.Selected)
+		}
+		content := container.NewBorder(
+			container.NewVBox(container.NewBorder(nil, nil, nil,
+				container.NewHBox(widget.NewButton("Compare...", showCompareEnvironments), duplicateBtn, deleteBtn), pick), nameEntry),
+			saveBtn, nil, nil,
+			varsEntry,
+		)
+		d := dialog.NewCustom("Environments", "Close", content, w)
+		d.Resize(fyne.NewSize(600, 500))
+		d.Show()
+	}
+
+	// Workspace management functions
+	setWorkspaceOptions := func() {
+		workspaceNames := []string{"+ New Workspace"}
+		for _, ws := range workspaces {
+			workspaceNames = append(workspaceNames, ws.Name)
+		}
+		workspaceSelect.Options = workspaceNames
+	}
+	createNewWorkspace := func() {
+		entry := widget.NewEntry()
+		form := dialog.NewForm("New Workspace", "Create", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Workspace Name", entry),
+		}, func(ok bool) {
+			if !ok || entry.Text == "" {
+				return
+			}
+			workspaces = append(workspaces, Workspace{Name: entry.Text, Collections: []Collection{}})
+			err := saveModel()
+			if err == nil {
+				setWorkspaceOptions()
+				workspaceSelect.SetSelected(entry.Text)
+				collectionSelect.Options = []string{"+ New Collection"}
+				collectionSelect.SetSelected("")
+				requestTree.Refresh()
+			}
+		}, w)
+		form.Show()
+	}
+
+	// Collection management functions
+	createNewCollection := func() {
+		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		entry := widget.NewEntry()
+		form := dialog.NewForm("New Collection", "Create", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Collection Name", entry),
+		}, func(ok bool) {
+			if !ok || entry.Text == "" {
+				return
+			}
+			for i, ws := range workspaces {
+				if ws.Name == workspaceSelect.Selected {
+					workspaces[i].Collections = append(workspaces[i].Collections, Collection{Name: entry.Text})
+					err := saveModel()
+					if err == nil {
+						// Update collection dropdown options
+						collectionOptions := []string{"+ New Collection"}
+						for _, col := range workspaces[i].Collections {
+							collectionOptions = append(collectionOptions, col.Name)
+						}
+						collectionSelect.Options = collectionOptions
+						collectionSelect.SetSelected(entry.Text)
+						selectedCollectionIdx = len(workspaces[i].Collections) - 1
+						requestTree.Refresh()
+					}
+				}
+			}
+		}, w)
+		form.Show()
+	}
+
+	// Request management functions
+	editRequestName := func(reqIdx int) {
+		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" || selectedCollectionIdx < 0 {
+			return
+		}
+		wsIdx := -1
+		for i, ws := range workspaces {
+			if ws.Name == workspaceSelect.Selected {
+				wsIdx = i
+				break
+			}
+		}
+		if wsIdx == -1 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+
+		req := workspaces[wsIdx].Collections[selectedCollectionIdx].request(reqIdx)
+		if req == nil {
+			return
+		}
+
+		entry := widget.NewEntry()
+		entry.SetText(req.Name)
+		form := dialog.NewForm("Edit Request Name", "Save", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Request Name", entry),
+		}, func(ok bool) {
+			if !ok || entry.Text == "" {
+				return
+			}
+			req.Name = entry.Text
+			err := saveModel()
+			if err == nil {
+				requestTree.Refresh()
+			}
+		}, w)
+		form.Show()
+	}
+
+	// Request tabs are set up once the response area exists; these hooks let the
+	// request list, form and workspace menu reach them
+	var openRequestTab func(reqIdx int, r APIRequest)
+	var requestsRemoved func(first, count int)
+	var requestsSwapped func(a, b int)
+	var requestMoved func(reqIdx int, toWorkspace string, toCollection, toRequest int)
+	var workspaceRenamed func(from, to string)
+	var workspaceRemoved func(name string)
+	var collectionRemoved func(colIdx int)
+	var updateTabDirty func()
+	// Set while the request list selection follows the active tab, so selecting doesn't open a tab
+	selectingTab := false
+
+	renameWorkspace := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		oldName := workspaces[wsIdx].Name
+		entry := widget.NewEntry()
+		entry.SetText(oldName)
+		form := dialog.NewForm("Rename Workspace", "Rename", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Workspace Name", entry),
+		}, func(ok bool) {
+			newName := strings.TrimSpace(entry.Text)
+			if !ok || newName == "" || newName == oldName {
+				return
+			}
+			if newName == "+ New Workspace" {
+				dialog.ShowError(fmt.Errorf("'%s' can't be used as a workspace name", newName), w)
+				return
+			}
+			for _, ws := range workspaces {
+				if ws.Name == newName {
+					dialog.ShowError(fmt.Errorf("a workspace named '%s' already exists", newName), w)
+					return
+				}
+			}
+			workspaces[wsIdx].Name = newName
+			if err := saveModel(); err != nil {
+				workspaces[wsIdx].Name = oldName
+				dialog.ShowError(err, w)
+				return
+			}
+			_ = cookies.rename(oldName, newName)
+			workspaceRenamed(oldName, newName)
+			// Set the selection directly: OnChanged would reset the selected collection
+			setWorkspaceOptions()
+			workspaceSelect.Selected = newName
+			workspaceSelect.Refresh()
+		}, w)
+		form.Show()
+	}
+
+	renameCollection := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("No Collection", "Select a collection first.", w)
+			return
+		}
+		colIdx := selectedCollectionIdx
+		oldName := workspaces[wsIdx].Collections[colIdx].Name
+		entry := widget.NewEntry()
+		entry.SetText(oldName)
+		form := dialog.NewForm("Rename Collection", "Rename", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Collection Name", entry),
+		}, func(ok bool) {
+			newName := strings.TrimSpace(entry.Text)
+			if !ok || newName == "" || newName == oldName {
+				return
+			}
+			if newName == "+ New Collection" {
+				dialog.ShowError(fmt.Errorf("'%s' can't be used as a collection name", newName), w)
+				return
+			}
+			for _, col := range workspaces[wsIdx].Collections {
+				if col.Name == newName {
+					dialog.ShowError(fmt.Errorf("a collection named '%s' already exists", newName), w)
+					return
+				}
+			}
+			// Flow steps find their requests by collection name
+			workspaces[wsIdx].Collections[colIdx].Name = newName
+			workspaces[wsIdx].renameFlowCollection(oldName, newName)
+			if err := saveModel(); err != nil {
+				workspaces[wsIdx].Collections[colIdx].Name = oldName
+				workspaces[wsIdx].renameFlowCollection(newName, oldName)
+				dialog.ShowError(err, w)
+				return
+			}
+			options := []string{"+ New Collection"}
+			for _, col := range workspaces[wsIdx].Collections {
+				options = append(options, col.Name)
+			}
+			// Set the selection directly: OnChanged would clear the selected request
+			collectionSelect.Options = options
+			collectionSelect.Selected = newName
+			collectionSelect.Refresh()
+			requestTree.Refresh()
+		}, w)
+		form.Show()
+	}
+
+	// Deleting a collection also deletes its request file
+	deleteCollection := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("No Collection", "Select a collection first.", w)
+			return
+		}
+		colIdx := selectedCollectionIdx
+		col := workspaces[wsIdx].Collections[colIdx]
+		dialog.ShowConfirm("Delete Collection",
+			fmt.Sprintf("Are you sure you want to delete the collection '%s'?", col.Name),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				collections := workspaces[wsIdx].Collections
+				workspaces[wsIdx].Collections = append(append([]Collection{}, collections[:colIdx]...), collections[colIdx+1:]...)
+				if err := saveModel(); err != nil {
+					workspaces[wsIdx].Collections = collections
+					dialog.ShowError(err, w)
+					return
+				}
+				_ = col.removeFile()
+				collectionRemoved(colIdx)
+				options := []string{"+ New Collection"}
+				for _, c := range workspaces[wsIdx].Collections {
+					options = append(options, c.Name)
+				}
+				collectionSelect.Options = options
+				// OnChanged resets selectedCollectionIdx and the request list
+				collectionSelect.ClearSelected()
+			}, w)
+	}
+
+	// Deleting a workspace also deletes its collections' request files and its cookies,
+	// then selects the first remaining workspace, or none when it was the last one
+	deleteWorkspace := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		ws := workspaces[wsIdx]
+		dialog.ShowConfirm("Delete Workspace",
+			fmt.Sprintf("Are you sure you want to delete the workspace '%s' with its %d collection(s), environments and flows?", ws.Name, len(ws.Collections)),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				previous := workspaces
+				workspaces = append(append([]Workspace{}, workspaces[:wsIdx]...), workspaces[wsIdx+1:]...)
+				if err := saveModel(); err != nil {
+					workspaces = previous
+					dialog.ShowError(err, w)
+					return
+				}
+				for i := range ws.Collections {
+					_ = ws.Collections[i].removeFile()
+				}
+				_ = cookies.remove(ws.Name)
+				workspaceRemoved(ws.Name)
+				setWorkspaceOptions()
+				if len(workspaces) > 0 {
+					workspaceSelect.SetSelected(workspaces[0].Name)
+				} else {
+					workspaceSelect.ClearSelected()
+				}
+			}, w)
+	}
+
+	deleteRequest := func(reqIdx int) {
+		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" || selectedCollectionIdx < 0 {
+			return
+		}
+		wsIdx := -1
+		for i, ws := range workspaces {
 			if ws.Name == workspaceSelect.Selected {
 				wsIdx = i
 				break
 			}
 		}
-		if wsIdx == -1 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
-			return
+		if wsIdx == -1 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+
+		coll := &workspaces[wsIdx].Collections[selectedCollectionIdx]
+		req := coll.request(reqIdx)
+		if req == nil {
+			return
+		}
+
+		reqName := req.Name
+		dialog.ShowConfirm("Delete Request",
+			fmt.Sprintf("Are you sure you want to delete the request '%s'?", reqName),
+			func(confirmed bool) {
+				if confirmed {
+					coll.removeRequest(reqIdx)
+					selectedRequestIdx = -1
+					requestTree.UnselectAll()
+					requestsRemoved(reqIdx, 1)
+					err := saveModel()
+					if err == nil {
+						requestTree.Refresh()
+					}
+				}
+			}, w)
+	}
+
+	// Swap a request with its neighbour delta places away (-1 up, 1 down) in the same
+	// folder; the selection and any tabs of the two requests follow them
+	moveRequest := func(reqIdx, delta int) {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+		other, ok := workspaces[wsIdx].Collections[selectedCollectionIdx].swapRequest(reqIdx, delta)
+		if !ok {
+			return
+		}
+		if err := saveModel(); err != nil {
+			dialog.ShowError(err, w)
+		}
+		requestTree.Refresh()
+		requestsSwapped(reqIdx, other)
+	}
+
+	// Move a request to another collection, in this workspace or another one, or to
+	// another folder of its own collection
+	showMoveRequest := func(reqIdx int) {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+		fromColIdx := selectedCollectionIdx
+		from := &workspaces[wsIdx].Collections[fromColIdx]
+		saved := from.request(reqIdx)
+		if saved == nil {
+			return
+		}
+		req := *saved
+		fromFolder := from.requestFolder(reqIdx)
+		const noFolder = "(no folder)"
+		workspaceNames := []string{}
+		for _, ws := range workspaces {
+			workspaceNames = append(workspaceNames, ws.Name)
+		}
+		toWsIdx, toColIdx := -1, -1
+		var folderPaths [][]int
+		folderChoice := widget.NewSelect(nil, nil)
+		collectionChoice := widget.NewSelect(nil, func(name string) {
+			toColIdx = -1
+			folderPaths = nil
+			folderChoice.Options = []string{noFolder}
+			for i, col := range workspaces[toWsIdx].Collections {
+				if col.Name == name {
+					toColIdx = i
+				}
+			}
+			if toColIdx != -1 {
+				// Folders of the target are only known once its requests are loaded
+				to := &workspaces[toWsIdx].Collections[toColIdx]
+				if err := to.load(); err != nil {
+					dialog.ShowError(err, w)
+					toColIdx = -1
+				} else {
+					var labels []string
+					folderPaths, labels = to.folderPaths()
+					folderChoice.Options = append(folderChoice.Options, labels...)
+				}
+			}
+			folderChoice.SetSelected(noFolder)
+		})
+		workspaceChoice := widget.NewSelect(workspaceNames, func(name string) {
+			for i, ws := range workspaces {
+				if ws.Name == name {
+					toWsIdx = i
+				}
+			}
+			names := []string{}
+			for _, col := range workspaces[toWsIdx].Collections {
+				names = append(names, col.Name)
+			}
+			collectionChoice.Options = names
+			collectionChoice.ClearSelected()
+			collectionChoice.Refresh()
+		})
+		workspaceChoice.SetSelected(workspaces[wsIdx].Name)
+		collectionChoice.SetSelected(from.Name)
+		dialog.ShowForm(fmt.Sprintf("Move '%s'", req.Name), "Move", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Workspace", workspaceChoice),
+			widget.NewFormItem("Collection", collectionChoice),
+			widget.NewFormItem("Folder", folderChoice),
+		}, func(ok bool) {
+			if !ok || toWsIdx == -1 || toColIdx == -1 {
+				return
+			}
+			var toFolder []int
+			for i, opt := range folderChoice.Options {
+				if opt == folderChoice.Selected && i > 0 {
+					toFolder = folderPaths[i-1]
+				}
+			}
+			if toWsIdx == wsIdx && toColIdx == fromColIdx && samePath(toFolder, fromFolder) {
+				return
+			}
+			to := &workspaces[toWsIdx].Collections[toColIdx]
+			fromPos, _ := from.siblings(reqIdx)
+			from.removeRequest(reqIdx)
+			toReqIdx := to.addRequest(toFolder, req)
+			if err := saveModel(); err != nil {
+				to.removeRequest(toReqIdx)
+				from.insertRequest(fromFolder, fromPos, req)
+				dialog.ShowError(err, w)
+				return
+			}
+			target := to.Name
+			if folderChoice.Selected != noFolder {
+				target += " / " + folderChoice.Selected
+			}
+			activity.add("Moved request %q from %q to %q / %q", req.Name, from.Name, workspaces[toWsIdx].Name, target)
+			requestTree.Refresh()
+			requestMoved(reqIdx, workspaces[toWsIdx].Name, toColIdx, toReqIdx)
+		}, w)
+	}
+
+	toggleRequestEnabled := func(reqIdx int) {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+		req := workspaces[wsIdx].Collections[selectedCollectionIdx].request(reqIdx)
+		if req == nil {
+			return
+		}
+		req.Enabled = !req.Enabled
+		err := saveModel()
+		if err == nil {
+			requestTree.Refresh()
+		}
+	}
+
+	// Compare two saved requests of the workspace side by side, highlighting differing lines
+	showCompareRequests := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		if err := workspaces[wsIdx].loadCollections(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		labels := []string{}
+		byLabel := map[string]APIRequest{}
+		for ci, col := range workspaces[wsIdx].Collections {
+			for ri, r := range col.allRequests() {
+				label := col.Name + " / " + r.Name
+				if _, dup := byLabel[label]; dup {
+					label = fmt.Sprintf("%s (%d)", label, ri+1)
+				}
+				labels = append(labels, label)
+				byLabel[label] = r
+				if ci == selectedCollectionIdx && ri == selectedRequestIdx && len(labels) > 1 {
+					// Put the selected request first so it is the default left side
+					labels[0], labels[len(labels)-1] = labels[len(labels)-1], labels[0]
+				}
+			}
+		}
+		if len(labels) < 2 {
+			dialog.ShowInformation("Compare", "The workspace needs at least two saved requests.", w)
+			return
+		}
+		leftText := widget.NewRichText()
+		rightText := widget.NewRichText()
+		leftText.Wrapping = fyne.TextWrapBreak
+		rightText.Wrapping = fyne.TextWrapBreak
+		leftSelect := widget.NewSelect(labels, nil)
+		rightSelect := widget.NewSelect(labels, nil)
+		update := func() {
+			left, lok := byLabel[leftSelect.Selected]
+			right, rok := byLabel[rightSelect.Selected]
+			if !lok || !rok {
+				return
+			}
+			leftFields, rightFields := requestFields(left), requestFields(right)
+			leftSegs, rightSegs := []widget.RichTextSegment{}, []widget.RichTextSegment{}
+			for i := range leftFields {
+				lChanged, rChanged := diffLines(leftFields[i].Lines, rightFields[i].Lines)
+				render := func(field requestField, changed []bool) []widget.RichTextSegment {
+					segs := []widget.RichTextSegment{&widget.TextSegment{Text: field.Label, Style: widget.RichTextStyleHeading}}
+					for j, line := range field.Lines {
+						style := widget.RichTextStyleCodeBlock
+						if changed[j] {
+							style.ColorName = theme.ColorNameError
+							style.TextStyle.Bold = true
+						}
+						segs = append(segs, &widget.TextSegment{Text: line, Style: style})
+					}
+					return segs
+				}
+				leftSegs = append(leftSegs, render(leftFields[i], lChanged)...)
+				rightSegs = append(rightSegs, render(rightFields[i], rChanged)...)
+			}
+			leftText.Segments = leftSegs
+			rightText.Segments = rightSegs
+			leftText.Refresh()
+			rightText.Refresh()
+		}
+		leftSelect.OnChanged = func(string) { update() }
+		rightSelect.OnChanged = func(string) { update() }
+		leftSelect.SetSelected(labels[0])
+		rightSelect.SetSelected(labels[1])
+		content := container.NewBorder(
+			container.NewGridWithColumns(2, leftSelect, rightSelect), nil, nil, nil,
+			container.NewVScroll(container.NewGridWithColumns(2, leftText, rightText)),
+		)
+		d := dialog.NewCustom("Compare Requests", "Close", content, w)
+		d.Resize(fyne.NewSize(1000, 650))
+		d.Show()
+	}
+
+	// Find and replace text in the URLs, headers and bodies of the selected collection's requests.
+	// Matches are previewed and nothing is saved until Replace All is confirmed.
+	showFindReplace := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("No Collection", "Select a collection first.", w)
+			return
+		}
+		coll := &workspaces[wsIdx].Collections[selectedCollectionIdx]
+		findEntry := widget.NewEntry()
+		findEntry.SetPlaceHolder("Find, e.g. api.old-host.com")
+		replaceEntry := widget.NewEntry()
+		replaceEntry.SetPlaceHolder("Replace with")
+		preview := widget.NewLabel("")
+		preview.Wrapping = fyne.TextWrapBreak
+		var pending []APIRequest
+		var replaceBtn *widget.Button
+		updatePreview := func() {
+			var matches []replaceMatch
+			pending, matches = replaceInRequests(coll.allRequests(), findEntry.Text, replaceEntry.Text)
+			if findEntry.Text == "" || len(matches) == 0 {
+				pending = nil
+				replaceBtn.Disable()
+				if findEntry.Text == "" {
+					preview.SetText("")
+				} else {
+					preview.SetText("No matches in this collection.")
+				}
+				return
+			}
+			total := 0
+			lines := []string{}
+			for _, m := range matches {
+				total += m.Count
+				lines = append(lines, m.summary())
+			}
+			preview.SetText(fmt.Sprintf("%d occurrence(s) in %d field(s):\n\n%s", total, len(matches), strings.Join(lines, "\n")))
+			replaceBtn.Enable()
+		}
+		var d dialog.Dialog
+		replaceBtn = widget.NewButton("Replace All", func() {
+			if pending == nil {
+				return
+			}
+			coll.setAllRequests(pending)
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			activity.add("Replaced %q with %q in collection %q", findEntry.Text, replaceEntry.Text, coll.Name)
+			requestTree.Refresh()
+			if req := coll.request(selectedRequestIdx); req != nil {
+				loadRequestIntoForm(*req)
+			}
+			d.Hide()
+		})
+		replaceBtn.Importance = widget.HighImportance
+		replaceBtn.Disable()
+		findEntry.OnChanged = func(string) { updatePreview() }
+		replaceEntry.OnChanged = func(string) { updatePreview() }
+		content := container.NewBorder(
+			widget.NewForm(
+				widget.NewFormItem("Find", findEntry),
+				widget.NewFormItem("Replace", replaceEntry),
+			),
+			container.NewHBox(layout.NewSpacer(), replaceBtn), nil, nil,
+			container.NewVScroll(preview),
+		)
+		d = dialog.NewCustom("Find and Replace in "+coll.Name, "Close", content, w)
+		d.Resize(fyne.NewSize(800, 550))
+		d.Show()
+	}
+
+	// Create workspace dropdown
+	workspaceNames := []string{"+ New Workspace"}
+	for _, ws := range workspaces {
+		workspaceNames = append(workspaceNames, ws.Name)
+	}
+	workspaceSelect = widget.NewSelect(workspaceNames, nil)
+
+	// Collection dropdown
+	collectionSelect = widget.NewSelect([]string{"+ New Collection"}, nil)
+
+	// Environment dropdown
+	envSelect = widget.NewSelect([]string{noEnvironment}, nil)
+	envSelect.SetSelected(noEnvironment)
+	manageEnvBtn := widget.NewButtonWithIcon("", theme.SettingsIcon(), showEnvironmentManager)
+
+	// Bind the selected environment to the selected collection, or unbind with "No Environment"
+	bindEnvBtn := widget.NewButton("Bind", func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("No Collection", "Select a collection to bind the environment to.", w)
+			return
+		}
+		env := envSelect.Selected
+		if env == noEnvironment {
+			env = ""
+		}
+		workspaces[wsIdx].Collections[selectedCollectionIdx].DefaultEnvironment = env
+		if err := saveModel(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		updateCollectionEnvLabel()
+	})
+
+	// Edit the variables shared by the selected collection's requests
+	collectionVarsBtn := widget.NewButton("Variables", func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("No Collection", "Select a collection first.", w)
+			return
+		}
+		coll := &workspaces[wsIdx].Collections[selectedCollectionIdx]
+		varsEntry := widget.NewMultiLineEntry()
+		varsEntry.SetPlaceHolder("KEY=VALUE, one per line")
+		varsEntry.SetMinRowsVisible(8)
+		varsEntry.SetText(formatVariables(coll.Variables))
+		d := dialog.NewCustomConfirm("Variables of "+coll.Name, "Save", "Cancel", varsEntry, func(ok bool) {
+			if !ok {
+				return
+			}
+			variablesMu.Lock()
+			coll.Variables = parseVariables(varsEntry.Text)
+			if len(coll.Variables) == 0 {
+				coll.Variables = nil
+			}
+			variablesMu.Unlock()
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}, w)
+		d.Resize(fyne.NewSize(500, 350))
+		d.Show()
+	})
+
+	// Collection shown in the request tree, or nil when none is selected
+	shownCollection := func() *Collection {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return nil
+		}
+		return &workspaces[wsIdx].Collections[selectedCollectionIdx]
+	}
+	// Variables for the form, which shows a request of the selected collection
+	formVariables := func(r APIRequest) map[string]string {
+		collection := ""
+		if coll := shownCollection(); coll != nil {
+			collection = coll.Name
+		}
+		return variablesFor(r, collection)
+	}
+
+	// Add a folder to the selected collection, inside the folder at parent (nil for the top level)
+	newFolder := func(parent []int) {
+		coll := shownCollection()
+		if coll == nil {
+			dialog.ShowInformation("No Collection", "Select a collection first.", w)
+			return
+		}
+		entry := widget.NewEntry()
+		dialog.ShowForm("New Folder", "Create", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Folder Name", entry),
+		}, func(ok bool) {
+			name := strings.TrimSpace(entry.Text)
+			if !ok || name == "" || !coll.addFolder(parent, name) {
+				return
+			}
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			requestTree.Refresh()
+			if len(parent) > 0 {
+				requestTree.OpenBranch(folderNodeID(parent))
+			}
+		}, w)
+	}
+
+	renameFolder := func(path []int) {
+		coll := shownCollection()
+		if coll == nil || coll.folderAt(path) == nil {
+			return
+		}
+		folder := coll.folderAt(path)
+		entry := widget.NewEntry()
+		entry.SetText(folder.Name)
+		dialog.ShowForm("Rename Folder", "Rename", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Folder Name", entry),
+		}, func(ok bool) {
+			name := strings.TrimSpace(entry.Text)
+			if !ok || name == "" {
+				return
+			}
+			coll.renameOpenFolders(coll.folderNames(path), name)
+			folder.Name = name
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			requestTree.Refresh()
+		}, w)
+	}
+
+	// Deleting a folder deletes its subfolders and requests too
+	deleteFolder := func(path []int) {
+		coll := shownCollection()
+		if coll == nil || coll.folderAt(path) == nil {
+			return
+		}
+		name := coll.folderAt(path).Name
+		dialog.ShowConfirm("Delete Folder",
+			fmt.Sprintf("Are you sure you want to delete the folder '%s' and everything in it?", name),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				coll.forgetOpenFolders(coll.folderNames(path))
+				first, count := coll.removeFolder(path)
+				selectedRequestIdx = -1
+				requestTree.UnselectAll()
+				if count > 0 {
+					requestsRemoved(first, count)
+				}
+				if err := saveModel(); err != nil {
+					dialog.ShowError(err, w)
+				}
+				requestTree.Refresh()
+				// Folders after the deleted one moved up, and their node IDs with them
+				restoreOpenFolders()
+			}, w)
+	}
+
+	// Requests of the selected collection as a tree of folders, each request with its
+	// edit/delete functionality
+	requestTree = widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			if coll := shownCollection(); coll != nil {
+				return coll.treeChildren(id)
+			}
+			return nil
+		},
+		func(id widget.TreeNodeID) bool {
+			_, _, isFolder := parseNodeID(id)
+			return isFolder
+		},
+		func(branch bool) fyne.CanvasObject {
+			nameLabel := widget.NewLabel("")
+			if branch {
+				// A folder: icon, name, and buttons to add a subfolder, rename and delete
+				return container.NewBorder(nil, nil, widget.NewIcon(theme.FolderIcon()),
+					container.NewHBox(
+						widget.NewButtonWithIcon("", theme.FolderNewIcon(), nil),
+						widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil),
+						widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+					),
+					nameLabel)
+			}
+			// Create a container with request name, enable toggle, edit button, and delete button
+			settingsIcon := widget.NewIcon(theme.SettingsIcon())
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil)
+			downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), nil)
+			toggleBtn := widget.NewButtonWithIcon("", theme.VisibilityIcon(), nil)
+			editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil)
+			moveBtn := widget.NewButtonWithIcon("", theme.MailForwardIcon(), nil)
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+
+			editBtn.Resize(fyne.NewSize(24, 24))
+			deleteBtn.Resize(fyne.NewSize(24, 24))
+
+			return container.NewBorder(nil, nil, settingsIcon,
+				container.NewHBox(upBtn, downBtn, toggleBtn, editBtn, moveBtn, deleteBtn),
+				nameLabel)
+		},
+		func(id widget.TreeNodeID, branch bool, o fyne.CanvasObject) {
+			coll := shownCollection()
+			if coll == nil {
+				return
+			}
+			// Cast to fyne.Container instead of container.Border
+			containerObj := o.(*fyne.Container)
+
+			// In a border container, the main object is at index 0, then the leading icon and the trailing buttons
+			nameLabel := containerObj.Objects[0].(*widget.Label)
+			buttonContainer := containerObj.Objects[2].(*fyne.Container)
+			path, reqIdx, _ := parseNodeID(id)
+			if branch {
+				folder := coll.folderAt(path)
+				if folder == nil {
+					return
+				}
+				nameLabel.SetText(folder.Name)
+				buttonContainer.Objects[0].(*widget.Button).OnTapped = func() { newFolder(path) }
+				buttonContainer.Objects[1].(*widget.Button).OnTapped = func() { renameFolder(path) }
+				buttonContainer.Objects[2].(*widget.Button).OnTapped = func() { deleteFolder(path) }
+				return
+			}
+			settingsIcon := containerObj.Objects[1].(*widget.Icon)
+			upBtn := buttonContainer.Objects[0].(*widget.Button)
+			downBtn := buttonContainer.Objects[1].(*widget.Button)
+			toggleBtn := buttonContainer.Objects[2].(*widget.Button)
+			editBtn := buttonContainer.Objects[3].(*widget.Button)
+			moveBtn := buttonContainer.Objects[4].(*widget.Button)
+			deleteBtn := buttonContainer.Objects[5].(*widget.Button)
+
+			req := coll.request(reqIdx)
+			if req == nil {
+				return
+			}
+			// Mark requests whose settings differ from the defaults
+			if hasCustomSettings(*req) {
+				settingsIcon.Show()
+			} else {
+				settingsIcon.Hide()
+			}
+
+			// Disabled requests are greyed out: italic, marked, and an "off" eye icon
+			if req.Enabled {
+				nameLabel.TextStyle = fyne.TextStyle{}
+				nameLabel.SetText(req.Name)
+				toggleBtn.SetIcon(theme.VisibilityIcon())
+			} else {
+				nameLabel.TextStyle = fyne.TextStyle{Italic: true}
+				nameLabel.SetText(req.Name + " (disabled)")
+				toggleBtn.SetIcon(theme.VisibilityOffIcon())
+			}
+
+			// The first request of a folder can't move up, nor the last one down
+			pos, count := coll.siblings(reqIdx)
+			if pos == 0 {
+				upBtn.Disable()
+			} else {
+				upBtn.Enable()
+			}
+			if pos == count-1 {
+				downBtn.Disable()
+			} else {
+				downBtn.Enable()
+			}
+			upBtn.OnTapped = func() {
+				moveRequest(reqIdx, -1)
+			}
+			downBtn.OnTapped = func() {
+				moveRequest(reqIdx, 1)
+			}
+			toggleBtn.OnTapped = func() {
+				toggleRequestEnabled(reqIdx)
+			}
+			editBtn.OnTapped = func() {
+				editRequestName(reqIdx)
+			}
+			moveBtn.OnTapped = func() {
+				showMoveRequest(reqIdx)
+			}
+			deleteBtn.OnTapped = func() {
+				deleteRequest(reqIdx)
+			}
+		},
+	)
+
+	// The folders open in the tree are remembered per collection and reopened when it is selected
+	restoringTree := false
+	restoreOpenFolders = func() {
+		restoringTree = true
+		requestTree.CloseAllBranches()
+		if coll := shownCollection(); coll != nil {
+			for _, names := range coll.OpenFolders {
+				if path, ok := coll.folderByNames(names); ok {
+					requestTree.OpenBranch(folderNodeID(path))
+				}
+			}
+		}
+		restoringTree = false
+	}
+	rememberOpenFolder := func(id widget.TreeNodeID, open bool) {
+		coll := shownCollection()
+		path, _, isFolder := parseNodeID(id)
+		if restoringTree || coll == nil || !isFolder || len(path) == 0 {
+			return
+		}
+		if names := coll.folderNames(path); names != nil {
+			coll.setFolderOpen(names, open)
+			scheduleAutoSave()
+		}
+	}
+	requestTree.OnBranchOpened = func(id widget.TreeNodeID) { rememberOpenFolder(id, true) }
+	requestTree.OnBranchClosed = func(id widget.TreeNodeID) { rememberOpenFolder(id, false) }
+
+	// Select a request in the tree, opening the folders it is in
+	revealRequest := func(reqIdx int) {
+		if coll := shownCollection(); coll != nil {
+			path := coll.requestFolder(reqIdx)
+			for i := 1; i <= len(path); i++ {
+				requestTree.OpenBranch(folderNodeID(path[:i]))
+			}
+		}
+		requestTree.Select(requestNodeID(reqIdx))
+	}
+
+	// Set up click handler to load request
+	requestTree.OnSelected = func(id widget.TreeNodeID) {
+		if selectingTab {
+			return
+		}
+		_, reqIdx, isFolder := parseNodeID(id)
+		if isFolder {
+			// Clicking a folder opens or closes it; the request selection stays
+			requestTree.ToggleBranch(id)
+			selectingTab = true
+			if selectedRequestIdx >= 0 {
+				requestTree.Select(requestNodeID(selectedRequestIdx))
+			} else {
+				requestTree.UnselectAll()
+			}
+			selectingTab = false
+			return
+		}
+		// Open the request in its own tab
+		if coll := shownCollection(); coll != nil {
+			if req := coll.request(reqIdx); req != nil {
+				openRequestTab(reqIdx, *req)
+			}
+		}
+	}
+
+	// Write form edits back to the loaded saved request when auto-save is on
+	syncFormToSelectedRequest := func() {
+		if loadingForm || !a.Preferences().Bool(prefAutoSave) || selectedRequestIdx < 0 {
+			return
+		}
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+		saved := workspaces[wsIdx].Collections[selectedCollectionIdx].request(selectedRequestIdx)
+		if saved == nil {
+			return
+		}
+		updated := formRequest()
+		updated.Name = saved.Name
+		updated.Enabled = saved.Enabled
+		*saved = updated
+		scheduleAutoSave()
+	}
+
+	// Non-blocking hint when a body is typed but the method will not send it
+	bodyIgnoredWarning := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	bodyIgnoredWarning.Hide()
+	updateBodyWarning := func() {
+		hasBody := strings.TrimSpace(bodyEntry.Text) != ""
+		switch bodyModeFromLabel(bodyModeSelect.Selected) {
+		case bodyModeFile:
+			hasBody = strings.TrimSpace(bodyFileEntry.Text) != ""
+		case bodyModeMultipart, bodyModeURLEncoded:
+			hasBody = len(formFields) > 0
+		}
+		if hasBody && !methodSendsBody(methodSelect.Selected) {
+			bodyIgnoredWarning.SetText(fmt.Sprintf("⚠ %s requests are sent without a body; the body below will be ignored.", methodSelect.Selected))
+			bodyIgnoredWarning.Show()
+		} else {
+			bodyIgnoredWarning.Hide()
+		}
+	}
+
+	// Called whenever any field of the request form changes
+	formChanged := func() {
+		updateBodyWarning()
+		syncFormToSelectedRequest()
+		if updateTabDirty != nil {
+			updateTabDirty()
+		}
+	}
+	methodSelect.OnChanged = func(string) { formChanged() }
+	urlEntry.OnChanged = func(string) { formChanged() }
+	// Header name completion for the line being typed, until it has a colon
+	headerSuggestionsRow := container.NewHBox()
+	updateHeaderSuggestions := func() {
+		headerSuggestionsRow.RemoveAll()
+		lines := strings.Split(headersEntry.Text, "\n")
+		row := headersEntry.CursorRow
+		if row < 0 || row >= len(lines) || strings.Contains(lines[row], ":") || isHeaderComment(lines[row]) {
+			return
+		}
+		for _, name := range headerSuggestions(lines[row], a.Preferences().StringList(prefRecentHeaders), 6) {
+			name := name
+			headerSuggestionsRow.Add(widget.NewButton(name, func() {
+				lines[row] = name + ": "
+				headersEntry.SetText(strings.Join(lines, "\n"))
+				headersEntry.CursorRow = row
+				headersEntry.CursorColumn = len(lines[row])
+				headersEntry.Refresh()
+				w.Canvas().Focus(headersEntry)
+			}))
+		}
+	}
+	headersEntry.OnChanged = func(string) {
+		formChanged()
+		updateHeaderSuggestions()
+	}
+
+	bodyEntry.OnChanged = func(string) { formChanged() }
+	bodyFileEntry.OnChanged = func(string) { formChanged() }
+	chunkedCheck.OnChanged = func(bool) { formChanged() }
+	hostEntry.OnChanged = func(string) { formChanged() }
+	envOverrideSelect.OnChanged = func(string) { formChanged() }
+	jsonataEntry.OnChanged = func(string) { formChanged() }
+	gzipCheck.OnChanged = func(bool) { formChanged() }
+	timeoutEntry.OnChanged = func(string) { formChanged() }
+	retriesEntry.OnChanged = func(string) { formChanged() }
+	expectedStatusEntry.OnChanged = func(string) { formChanged() }
+	languageEntry.OnChanged = func(string) { formChanged() }
+	authTokenEntry.OnChanged = func(string) { formChanged() }
+	authUsernameEntry.OnChanged = func(string) { formChanged() }
+	authPasswordEntry.OnChanged = func(string) { formChanged() }
+	followRedirectsCheck.OnChanged = func(bool) { formChanged() }
+	insecureCheck.OnChanged = func(bool) { formChanged() }
+	proxyEntry.OnChanged = func(string) { formChanged() }
+	conditionalCheck.OnChanged = func(bool) { formChanged() }
+
+	// Typing {{ in the URL, headers or body pops up the variables available to the request
+	attachVariableInserter := func(entry *shortcutEntry) {
+		onChanged := entry.OnChanged
+		entry.OnChanged = func(text string) {
+			onChanged(text)
+			if loadingForm || !variableTriggered(text, entry.CursorRow, entry.CursorColumn) {
+				return
+			}
+			vars := formVariables(formRequest())
+			names := make([]string, 0, len(vars))
+			for name := range vars {
+				names = append(names, name)
+			}
+			if len(names) == 0 {
+				return
+			}
+			sort.Strings(names)
+			row, col := entry.CursorRow, entry.CursorColumn
+			items := []*fyne.MenuItem{}
+			for _, name := range names {
+				name := name
+				items = append(items, fyne.NewMenuItem(name, func() {
+					newText, newCol := insertAtCursor(entry.Text, row, col, name+"}}")
+					entry.SetText(newText)
+					entry.CursorRow = row
+					entry.CursorColumn = newCol
+					entry.Refresh()
+					w.Canvas().Focus(entry)
+				}))
+			}
+			// Open just below the line being edited
+			pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(entry)
+			lineHeight := theme.TextSize() + theme.InnerPadding()
+			offset := fyne.Min(float32(row+1)*lineHeight+theme.InnerPadding(), entry.Size().Height)
+			widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), w.Canvas(), pos.AddXY(0, offset))
+		}
+	}
+	attachVariableInserter(urlEntry)
+	attachVariableInserter(headersEntry)
+	attachVariableInserter(bodyEntry)
+
+	// App settings dialog
+	// App-wide proxy for requests without their own: "" follows the environment variables
+	proxySetting := func() string {
+		switch a.Preferences().String(prefProxyMode) {
+		case proxyModeNone:
+			return proxyDirect
+		case proxyModeCustom:
+			return strings.TrimSpace(a.Preferences().String(prefProxyURL))
+		}
+		return ""
+	}
+
+	showSettings := func() {
+		autoSaveCheck := widget.NewCheck("Auto-save changes to the loaded request", nil)
+		autoSaveCheck.SetChecked(a.Preferences().Bool(prefAutoSave))
+		slowEntry := widget.NewEntry()
+		slowEntry.SetText(strconv.Itoa(a.Preferences().IntWithFallback(prefSlowResponseMs, defaultSlowResponseMs)))
+		largeEntry := widget.NewEntry()
+		largeEntry.SetText(strconv.Itoa(a.Preferences().IntWithFallback(prefLargeResponseKB, defaultLargeResponseKB)))
+		tabOptions := []string{keepCurrentTab}
+		for _, item := range responseTabs.Items {
+			tabOptions = append(tabOptions, item.Text)
+		}
+		defaultTabSelect := widget.NewSelect(tabOptions, nil)
+		defaultTabSelect.SetSelected(a.Preferences().StringWithFallback(prefDefaultResponseTab, keepCurrentTab))
+		defaultTimeoutEntry := widget.NewEntry()
+		defaultTimeoutEntry.SetText(strconv.Itoa(a.Preferences().IntWithFallback(prefDefaultTimeoutSeconds, defaultTimeoutSeconds)))
+		maxResponseEntry := widget.NewEntry()
+		maxResponseEntry.SetText(strconv.Itoa(a.Preferences().IntWithFallback(prefMaxResponseMB, defaultMaxResponseMB)))
+		prettyAnyCheck := widget.NewCheck("Pretty-print valid JSON whatever its Content-Type", nil)
+		prettyAnyCheck.SetChecked(a.Preferences().Bool(prefPrettyAnyContentType))
+		globalHeadersEntry := widget.NewMultiLineEntry()
+		globalHeadersEntry.SetPlaceHolder("Key: Value, one per line")
+		globalHeadersEntry.SetMinRowsVisible(4)
+		globalHeadersEntry.SetText(a.Preferences().String(prefGlobalHeaders))
+		globalVariablesEntry := widget.NewMultiLineEntry()
+		globalVariablesEntry.SetPlaceHolder("KEY=VALUE, one per line")
+		globalVariablesEntry.SetMinRowsVisible(4)
+		globalVariablesEntry.SetText(a.Preferences().String(prefGlobalVariables))
+		namingTemplateEntry := widget.NewEntry()
+		namingTemplateEntry.SetPlaceHolder(defaultNamingTemplate)
+		namingTemplateEntry.SetText(a.Preferences().String(prefNamingTemplate))
+		precedenceSelect := widget.NewSelect(variablePrecedenceOptions(), nil)
+		precedenceSelect.SetSelected(strings.Join(parseVariablePrecedence(a.Preferences().String(prefVariablePrecedence)), precedenceSeparator))
+		proxyURLEntry := widget.NewEntry()
+		proxyURLEntry.SetPlaceHolder("http://proxy.example.com:3128")
+		proxyURLEntry.SetText(a.Preferences().String(prefProxyURL))
+		proxyModeSelect := widget.NewSelect([]string{proxyModeSystem, proxyModeNone, proxyModeCustom}, func(mode string) {
+			if mode == proxyModeCustom {
+				proxyURLEntry.Enable()
+			} else {
+				proxyURLEntry.Disable()
+			}
+		})
+		proxyModeSelect.SetSelected(a.Preferences().StringWithFallback(prefProxyMode, proxyModeSystem))
+		insecureWarning := widget.NewLabel("")
+		insecureWarning.Importance = widget.DangerImportance
+		insecureWarning.Wrapping = fyne.TextWrapWord
+		insecureAllCheck := widget.NewCheck("Skip TLS certificate verification for every request", func(checked bool) {
+			if checked {
+				insecureWarning.SetText("Insecure: certificates are not checked, so anyone on the network can read and change the traffic. Use only for development servers with self-signed certificates.")
+				insecureWarning.Show()
+			} else {
+				insecureWarning.Hide()
+			}
+		})
+		insecureWarning.Hide()
+		insecureAllCheck.SetChecked(a.Preferences().Bool(prefInsecureSkipVerify))
+		dialog.ShowForm("Settings", "Save", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Auto-save", autoSaveCheck),
+			{Text: "Slow response (ms)", Widget: slowEntry, HintText: "Orange above the threshold, red above double"},
+			widget.NewFormItem("Large response (KB)", largeEntry),
+			{Text: "Max response (MB)", Widget: maxResponseEntry, HintText: "Longer bodies are truncated"},
+			{Text: "Default timeout (s)", Widget: defaultTimeoutEntry, HintText: "For requests without their own timeout; 0 = no timeout"},
+			{Text: "Response tab", Widget: defaultTabSelect, HintText: "Shown after each response arrives"},
+			{Text: "Request names", Widget: namingTemplateEntry, HintText: "Suggested when saving; {{method}}, {{host}}, {{path}} and {{url}} are filled in"},
+			{Text: "Pretty-print", Widget: prettyAnyCheck, HintText: "For servers that send JSON with a wrong Content-Type"},
+			{Text: "Global headers", Widget: globalHeadersEntry, HintText: "Added to every request that doesn't set them itself"},
+			{Text: "Global variables", Widget: globalVariablesEntry, HintText: "Available to every request in every workspace"},
+			{Text: "Variable precedence", Widget: precedenceSelect, HintText: "When a name is defined in several scopes, the leftmost wins"},
+			{Text: "Proxy", Widget: proxyModeSelect, HintText: "For requests without a proxy of their own"},
+			widget.NewFormItem("Proxy URL", proxyURLEntry),
+			{Text: "TLS", Widget: container.NewVBox(insecureAllCheck, insecureWarning)},
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			if proxyModeSelect.Selected == proxyModeCustom {
+				if !validProxyURL(strings.TrimSpace(proxyURLEntry.Text)) {
+					dialog.ShowError(fmt.Errorf("invalid proxy URL %q; the other settings were not saved", proxyURLEntry.Text), w)
+					return
+				}
+			}
+			a.Preferences().SetString(prefProxyMode, proxyModeSelect.Selected)
+			a.Preferences().SetString(prefProxyURL, strings.TrimSpace(proxyURLEntry.Text))
+			a.Preferences().SetBool(prefInsecureSkipVerify, insecureAllCheck.Checked)
+			a.Preferences().SetBool(prefAutoSave, autoSaveCheck.Checked)
+			a.Preferences().SetBool(prefPrettyAnyContentType, prettyAnyCheck.Checked)
+			a.Preferences().SetString(prefGlobalHeaders, globalHeadersEntry.Text)
+			a.Preferences().SetString(prefGlobalVariables, globalVariablesEntry.Text)
+			a.Preferences().SetString(prefVariablePrecedence, precedenceSelect.Selected)
+			a.Preferences().SetString(prefDefaultResponseTab, defaultTabSelect.Selected)
+			a.Preferences().SetString(prefNamingTemplate, strings.TrimSpace(namingTemplateEntry.Text))
+			if v, err := strconv.Atoi(strings.TrimSpace(slowEntry.Text)); err == nil && v > 0 {
+				a.Preferences().SetInt(prefSlowResponseMs, v)
+			}
+			if v, err := strconv.Atoi(strings.TrimSpace(largeEntry.Text)); err == nil && v > 0 {
+				a.Preferences().SetInt(prefLargeResponseKB, v)
+			}
+			if v, err := strconv.Atoi(strings.TrimSpace(maxResponseEntry.Text)); err == nil && v > 0 {
+				a.Preferences().SetInt(prefMaxResponseMB, v)
+			}
+			if v, err := strconv.Atoi(strings.TrimSpace(defaultTimeoutEntry.Text)); err == nil && v >= 0 {
+				a.Preferences().SetInt(prefDefaultTimeoutSeconds, v)
+			}
+			if autoSaveCheck.Checked {
+				saveIndicator.SetText("Auto-save on")
+			} else {
+				saveIndicator.SetText("")
+			}
+		}, w)
+	}
+	settingsBtn := widget.NewButtonWithIcon("Settings", theme.SettingsIcon(), showSettings)
+	var workspaceMenuBtn *widget.Button
+	workspaceMenuBtn = widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), func() {
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem("Rename Workspace...", renameWorkspace),
+			fyne.NewMenuItem("Delete Workspace...", deleteWorkspace),
+		)
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(workspaceMenuBtn)
+		widget.ShowPopUpMenuAtPosition(menu, w.Canvas(), pos.AddXY(0, workspaceMenuBtn.Size().Height))
+	})
+	var collectionMenuBtn *widget.Button
+	collectionMenuBtn = widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), func() {
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem("Rename Collection...", renameCollection),
+			fyne.NewMenuItem("Delete Collection...", deleteCollection),
+		)
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(collectionMenuBtn)
+		widget.ShowPopUpMenuAtPosition(menu, w.Canvas(), pos.AddXY(0, collectionMenuBtn.Size().Height))
+	})
+	if a.Preferences().Bool(prefAutoSave) {
+		saveIndicator.SetText("Auto-save on")
+	}
+
+	// Set up workspace selection callback after all widgets are created
+	workspaceSelect.OnChanged = func(selected string) {
+		if selected == "+ New Workspace" {
+			createNewWorkspace()
+			return
+		}
+		// Update collection dropdown when workspace changes
+		selectedCollectionIdx = -1
+		selectedRequestIdx = -1
+		requestTree.UnselectAll()
+		collectionOptions := []string{"+ New Collection"}
+		for _, ws := range workspaces {
+			if ws.Name == selected {
+				for _, col := range ws.Collections {
+					collectionOptions = append(collectionOptions, col.Name)
+				}
+				break
+			}
+		}
+		collectionSelect.Options = collectionOptions
+		collectionSelect.SetSelected("")
+		refreshEnvironmentOptions()
+		updateCollectionEnvLabel()
+		if refreshFlowOptions != nil {
+			refreshFlowOptions()
+		}
+		requestTree.Refresh()
+		restoreOpenFolders()
+	}
+
+	// Set up collection selection callback
+	collectionSelect.OnChanged = func(selected string) {
+		if selected == "+ New Collection" {
+			createNewCollection()
+			return
+		}
+		// Find the collection index
+		selectedCollectionIdx = -1
+		selectedRequestIdx = -1
+		requestTree.UnselectAll()
+		for _, ws := range workspaces {
+			if ws.Name == workspaceSelect.Selected {
+				for i, col := range ws.Collections {
+					if col.Name == selected {
+						selectedCollectionIdx = i
+						break
+					}
+				}
+				break
+			}
+		}
+		// Requests of a collection are read from disk the first time it is selected
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 && selectedCollectionIdx >= 0 {
+			if err := workspaces[wsIdx].Collections[selectedCollectionIdx].load(); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+		// Switch to the collection's environment if it is bound to one that still exists
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 && selectedCollectionIdx >= 0 {
+			if env := workspaces[wsIdx].Collections[selectedCollectionIdx].DefaultEnvironment; env != "" {
+				for _, opt := range envSelect.Options {
+					if opt == env {
+						envSelect.SetSelected(env)
+					}
+				}
+			}
+		}
+		updateCollectionEnvLabel()
+		requestTree.Refresh()
+		restoreOpenFolders()
+	}
+
+	if len(workspaceNames) > 1 {
+		workspaceSelect.SetSelected(workspaceNames[1]) // Select first actual workspace, not "+ New Workspace"
+	}
+
+	// Initialize collection options for the selected workspace
+	if workspaceSelect.Selected != "" && workspaceSelect.Selected != "+ New Workspace" {
+		collectionOptions := []string{"+ New Collection"}
+		for _, ws := range workspaces {
+			if ws.Name == workspaceSelect.Selected {
+				for _, col := range ws.Collections {
+					collectionOptions = append(collectionOptions, col.Name)
+				}
+				break
+			}
+		}
+		collectionSelect.Options = collectionOptions
+	}
+
+	// Flows: ordered sequences of saved requests, stored on the workspace
+	flowSelect := widget.NewSelect([]string{}, nil)
+	flowSelect.PlaceHolder = "Select a flow"
+	refreshFlowOptions = func() {
+		options := []string{}
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 {
+			for _, f := range workspaces[wsIdx].Flows {
+				options = append(options, f.Name)
+			}
+		}
+		flowSelect.Options = options
+		flowSelect.ClearSelected()
+		flowSelect.Refresh()
+	}
+	selectedFlowIdx := func() int {
+		if wsIdx := currentWorkspaceIdx(); wsIdx != -1 {
+			for i, f := range workspaces[wsIdx].Flows {
+				if f.Name == flowSelect.Selected {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	// flowIdx -1 creates a new flow
+	showFlowEditor := func(flowIdx int) {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		if err := workspaces[wsIdx].loadCollections(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		flow := Flow{}
+		if flowIdx >= 0 {
+			flow = workspaces[wsIdx].Flows[flowIdx]
+		}
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("Flow name")
+		nameEntry.SetText(flow.Name)
+		collectionNames := []string{}
+		for _, col := range workspaces[wsIdx].Collections {
+			collectionNames = append(collectionNames, col.Name)
+		}
+		requestNames := func(collection string) []string {
+			names := []string{}
+			for _, col := range workspaces[wsIdx].Collections {
+				if col.Name == collection {
+					for _, r := range col.allRequests() {
+						names = append(names, r.Name)
+					}
+				}
+			}
+			return names
+		}
+
+		type stepRow struct {
+			collection, request, condition *widget.Select
+			delay, branches                *widget.Entry
+			number                         *widget.Label
+			box                            *fyne.Container
+		}
+		rows := []*stepRow{}
+		rowsBox := container.NewVBox()
+		// Branch targets are step numbers, so show them
+		renumber := func() {
+			for i, r := range rows {
+				r.number.SetText(fmt.Sprintf("%d.", i+1))
+			}
+		}
+		addStep := func(step FlowStep) {
+			row := &stepRow{}
+			row.request = widget.NewSelect(requestNames(step.Collection), nil)
+			row.request.PlaceHolder = "Request"
+			row.collection = widget.NewSelect(collectionNames, func(selected string) {
+				row.request.Options = requestNames(selected)
+				row.request.ClearSelected()
+				row.request.Refresh()
+			})
+			row.collection.PlaceHolder = "Collection"
+			row.collection.SetSelected(step.Collection)
+			row.request.SetSelected(step.Request)
+			row.delay = widget.NewEntry()
+			row.delay.SetPlaceHolder("Delay before (ms)")
+			if step.DelayMs > 0 {
+				row.delay.SetText(strconv.Itoa(step.DelayMs))
+			}
+			row.condition = widget.NewSelect(flowConditions, nil)
+			row.condition.SetSelected(flowConditionAlways)
+			if step.Condition != "" {
+				row.condition.SetSelected(step.Condition)
+			}
+			row.branches = widget.NewEntry()
+			row.branches.SetPlaceHolder("Then, e.g. status >= 400 -> end")
+			row.branches.SetText(formatFlowBranches(step.Branches))
+			row.number = widget.NewLabel("")
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				for i, r := range rows {
+					if r == row {
+						rows = append(rows[:i], rows[i+1:]...)
+						break
+					}
+				}
+				rowsBox.Remove(row.box)
+				renumber()
+			})
+			row.box = container.NewBorder(nil, nil, row.number, removeBtn,
+				container.NewGridWithColumns(5, row.collection, row.request, row.delay, row.condition, row.branches))
+			rows = append(rows, row)
+			rowsBox.Add(row.box)
+			renumber()
+		}
+		for _, step := range flow.Steps {
+			addStep(step)
+		}
+		if len(flow.Steps) == 0 {
+			addStep(FlowStep{})
+		}
+
+		content := container.NewBorder(
+			container.NewVBox(nameEntry, widget.NewLabel("Steps run top to bottom. The condition is checked against the previous step.\n"+
+				"Branches pick the step to run next: `status == 404 -> 4; $.state == \"pending\" -> 2`; the first match wins, \"end\" stops the flow.")),
+			widget.NewButtonWithIcon("Add Step", theme.ContentAddIcon(), func() { addStep(FlowStep{}) }),
+			nil, nil,
+			container.NewVScroll(rowsBox),
+		)
+		d := dialog.NewCustomConfirm("Edit Flow", "Save", "Cancel", content, func(ok bool) {
+			if !ok {
+				return
+			}
+			name := strings.TrimSpace(nameEntry.Text)
+			if name == "" {
+				dialog.ShowInformation("No Name", "Please enter a flow name.", w)
+				return
+			}
+			edited := Flow{Name: name}
+			// Incomplete steps are dropped, which would shift the numbers branches point at
+			hasBranches := false
+			for _, row := range rows {
+				hasBranches = hasBranches || strings.TrimSpace(row.branches.Text) != ""
+			}
+			for i, row := range rows {
+				if row.collection.Selected == "" || row.request.Selected == "" {
+					if hasBranches {
+						dialog.ShowInformation("Incomplete Step", fmt.Sprintf("Step %d has no request. Pick one or remove the step, so branch targets keep their numbers.", i+1), w)
+						return
+					}
+					continue
+				}
+				delay, _ := strconv.Atoi(strings.TrimSpace(row.delay.Text))
+				branches, err := parseFlowBranches(row.branches.Text, len(rows))
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("Step %d: %v", i+1, err), w)
+					return
+				}
+				edited.Steps = append(edited.Steps, FlowStep{
+					Collection: row.collection.Selected,
+					Request:    row.request.Selected,
+					DelayMs:    delay,
+					Condition:  row.condition.Selected,
+					Branches:   branches,
+				})
+			}
+			if flowIdx >= 0 {
+				workspaces[wsIdx].Flows[flowIdx] = edited
+			} else {
+				workspaces[wsIdx].Flows = append(workspaces[wsIdx].Flows, edited)
+			}
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			refreshFlowOptions()
+			flowSelect.SetSelected(name)
+		}, w)
+		d.Resize(fyne.NewSize(900, 500))
+		d.Show()
+	}
+
+	// Run the selected flow off the UI goroutine with a step-by-step log
+	runSelectedFlow := func() {
+		wsIdx := currentWorkspaceIdx()
+		flowIdx := selectedFlowIdx()
+		if wsIdx == -1 || flowIdx == -1 {
+			dialog.ShowInformation("No Flow", "Select a flow to run.", w)
+			return
+		}
+		if err := workspaces[wsIdx].loadCollections(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		flow := workspaces[wsIdx].Flows[flowIdx]
+		logEntry := widget.NewMultiLineEntry()
+		logEntry.Wrapping = fyne.TextWrapWord
+		logWin := a.NewWindow("Flow: " + flow.Name)
+		logWin.SetContent(container.NewVScroll(logEntry))
+		logWin.Resize(fyne.NewSize(900, 500))
+		logWin.Show()
+		ws := workspaces[wsIdx]
+		activity.add("Running flow %q", flow.Name)
+		go runFlow(ws, flow, cookies.forWorkspace(ws.Name), func(collection string, r APIRequest) APIRequest {
+			r = withGlobalHeaders(r, parseHeaders(a.Preferences().String(prefGlobalHeaders)))
+			r = withDefaultTimeout(r, a.Preferences().IntWithFallback(prefDefaultTimeoutSeconds, defaultTimeoutSeconds))
+			r = withConnectionDefaults(r, proxySetting(), a.Preferences().Bool(prefInsecureSkipVerify))
+			return resolveRequest(r, variablesFor(r, collection))
+		}, func(r APIRequest, res *exchangeResult) []string {
+			values, problems := extractValues(r.Extract, res.Header, res.Body)
+			if len(values) > 0 {
+				if _, err := storeVariables(r, values); err != nil {
+					problems = append(problems, err.Error())
+				}
+			}
+			return problems
+		}, func(format string, args ...interface{}) {
+			line := time.Now().Format("15:04:05 ") + fmt.Sprintf(format, args...)
+			logEntry.SetText(logEntry.Text + line + "\n")
+			activity.add("Flow %q: %s", flow.Name, fmt.Sprintf(format, args...))
+		})
+	}
+
+	// Send every request of the selected collection in order, off the UI goroutine,
+	// with a results row per request
+	showCollectionRunner := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("No Collection", "Select a collection to run.", w)
+			return
+		}
+		coll := workspaces[wsIdx].Collections[selectedCollectionIdx]
+		// Folders run in tree order, before the requests outside any folder
+		requests := coll.allRequests()
+		if len(requests) == 0 {
+			dialog.ShowInformation("Empty Collection", "The collection has no requests to run.", w)
+			return
+		}
+		var mu sync.Mutex
+		var results []*runResult
+		table := widget.NewTable(
+			func() (int, int) { return len(requests) + 1, 4 },
+			func() fyne.CanvasObject { return canvas.NewText("", theme.ForegroundColor()) },
+			func(id widget.TableCellID, obj fyne.CanvasObject) {
+				text := obj.(*canvas.Text)
+				text.TextStyle = fyne.TextStyle{}
+				text.Color = theme.ForegroundColor()
+				if id.Row == 0 {
+					text.Text = []string{"Request", "Status", "Time", "Size"}[id.Col]
+					text.TextStyle.Bold = true
+					text.Refresh()
+					return
+				}
+				r := requests[id.Row-1]
+				cells := []string{r.Method + " " + r.Name, "", "", ""}
+				mu.Lock()
+				if id.Row-1 < len(results) && results[id.Row-1] != nil {
+					res := results[id.Row-1]
+					cells[1] = res.statusText()
+					if res.Err == nil {
+						cells[2] = fmt.Sprintf("%d ms", res.Elapsed.Milliseconds())
+						cells[3] = formatSize(res.Size)
+					}
+					if res.failed() {
+						text.Color = theme.ErrorColor()
+					}
+					if res.Skipped {
+						text.Color = theme.DisabledColor()
+					}
+				}
+				mu.Unlock()
+				text.Text = cells[id.Col]
+				text.Refresh()
+			},
+		)
+		table.SetColumnWidth(0, 380)
+		table.SetColumnWidth(1, 260)
+		table.SetColumnWidth(2, 100)
+		table.SetColumnWidth(3, 100)
+
+		delayEntry := widget.NewEntry()
+		delayEntry.SetText("0")
+		stopOnErrorCheck := widget.NewCheck("Stop on first error", nil)
+		progress := widget.NewProgressBar()
+		summary := widget.NewLabel(fmt.Sprintf("%d requests", len(requests)))
+		var stop chan struct{}
+		var started time.Time
+		var runWin fyne.Window
+		var startBtn, stopBtn, exportBtn *widget.Button
+		startBtn = widget.NewButtonWithIcon("Run", theme.MediaPlayIcon(), func() {
+			delayMs, err := strconv.Atoi(strings.TrimSpace(delayEntry.Text))
+			if err != nil || delayMs < 0 {
+				dialog.ShowError(fmt.Errorf("Delay must be a whole number of milliseconds"), w)
+				return
+			}
+			opts := runOptions{
+				Delay:       time.Duration(delayMs) * time.Millisecond,
+				StopOnError: stopOnErrorCheck.Checked,
+				Jar:         cookies.forWorkspace(workspaces[wsIdx].Name),
+			}
+			mu.Lock()
+			results = make([]*runResult, len(requests))
+			mu.Unlock()
+			table.Refresh()
+			progress.SetValue(0)
+			summary.SetText("Running...")
+			stop = make(chan struct{})
+			started = time.Now()
+			startBtn.Disable()
+			stopBtn.Enable()
+			exportBtn.Disable()
+			activity.add("Running collection %q (%d requests)", coll.Name, len(requests))
+			go func(stop chan struct{}) {
+				passed, failed, skipped := 0, 0, 0
+				sent := runCollection(requests, opts, func(r APIRequest) APIRequest {
+					r = withGlobalHeaders(r, parseHeaders(a.Preferences().String(prefGlobalHeaders)))
+					r = withDefaultTimeout(r, a.Preferences().IntWithFallback(prefDefaultTimeoutSeconds, defaultTimeoutSeconds))
+					r = withConnectionDefaults(r, proxySetting(), a.Preferences().Bool(prefInsecureSkipVerify))
+					return resolveRequest(r, variablesFor(r, coll.Name))
+				}, func(r APIRequest, res *exchangeResult) []string {
+					values, problems := extractValues(r.Extract, res.Header, res.Body)
+					if len(values) > 0 {
+						if _, err := storeVariables(r, values); err != nil {
+							problems = append(problems, err.Error())
+						}
+					}
+					return problems
+				}, func(i int, res runResult) {
+					mu.Lock()
+					results[i] = &res
+					mu.Unlock()
+					switch {
+					case res.Skipped:
+						skipped++
+					case res.failed():
+						failed++
+					default:
+						passed++
+					}
+					for _, problem := range res.Problems {
+						activity.add("Collection %q: %s: extract %s", coll.Name, res.Name, problem)
+					}
+					for _, t := range res.Tests {
+						if !t.Passed {
+							activity.add("Collection %q: %s: test failed: %s (%s)", coll.Name, res.Name, t.Assertion, t.Detail)
+						}
+					}
+					table.Refresh()
+					progress.SetValue(float64(i+1) / float64(len(requests)))
+					status := fmt.Sprintf("%d/%d done: %d passed, %d failed", i+1, len(requests), passed, failed)
+					if skipped > 0 {
+						status += fmt.Sprintf(", %d skipped", skipped)
+					}
+					summary.SetText(status)
+				}, stop)
+				done := fmt.Sprintf("Finished: %d passed, %d failed", passed, failed)
+				if skipped > 0 {
+					done += fmt.Sprintf(", %d skipped", skipped)
+				}
+				if notRun := len(requests) - sent; notRun > 0 {
+					done += fmt.Sprintf(", %d not run", notRun)
+				}
+				summary.SetText(done)
+				activity.add("Collection %q: %s", coll.Name, done)
+				startBtn.Enable()
+				stopBtn.Disable()
+				exportBtn.Enable()
+			}(stop)
+		})
+		startBtn.Importance = widget.HighImportance
+		stopBtn = widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), func() {
+			close(stop)
+			stopBtn.Disable()
+		})
+		stopBtn.Disable()
+		// JUnit XML of the last run, for CI servers to show as test results
+		exportBtn = widget.NewButtonWithIcon("Export JUnit", theme.DocumentSaveIcon(), func() {
+			mu.Lock()
+			data, err := junitReport(coll.Name, started, results)
+			mu.Unlock()
+			if err != nil {
+				dialog.ShowError(err, runWin)
+				return
+			}
+			save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil || writer == nil {
+					return
+				}
+				defer writer.Close()
+				if _, err := writer.Write(data); err != nil {
+					dialog.ShowError(fmt.Errorf("Write error: %v", err), runWin)
+					return
+				}
+				activity.add("Exported JUnit report of collection %q to %s", coll.Name, writer.URI().Name())
+			}, runWin)
+			save.SetFileName(sanitizeFileName(coll.Name + ".junit.xml"))
+			save.Show()
+		})
+		exportBtn.Disable()
+
+		runWin = a.NewWindow("Run Collection: " + coll.Name)
+		runWin.SetOnClosed(func() {
+			if !stopBtn.Disabled() {
+				close(stop)
+			}
+		})
+		runWin.SetContent(container.NewBorder(
+			container.NewVBox(
+				container.NewHBox(
+					widget.NewLabel("Delay (ms)"),
+					container.NewGridWrap(fyne.NewSize(100, delayEntry.MinSize().Height), delayEntry),
+					stopOnErrorCheck,
+					layout.NewSpacer(),
+					startBtn,
+					stopBtn,
+					exportBtn,
+				),
+				progress,
+				summary,
+			),
+			nil, nil, nil,
+			table,
+		))
+		runWin.Resize(fyne.NewSize(900, 550))
+		runWin.Show()
+	}
+
+	newFlowBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() { showFlowEditor(-1) })
+	editFlowBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+		if idx := selectedFlowIdx(); idx != -1 {
+			showFlowEditor(idx)
+		}
+	})
+	deleteFlowBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		wsIdx := currentWorkspaceIdx()
+		idx := selectedFlowIdx()
+		if wsIdx == -1 || idx == -1 {
+			return
+		}
+		dialog.ShowConfirm("Delete Flow",
+			fmt.Sprintf("Are you sure you want to delete the flow '%s'?", flowSelect.Selected),
+			func(confirmed bool) {
+				if confirmed {
+					flows := workspaces[wsIdx].Flows
+					workspaces[wsIdx].Flows = append(flows[:idx], flows[idx+1:]...)
+					_ = saveModel()
+					refreshFlowOptions()
+				}
+			}, w)
+	})
+	runFlowBtn := widget.NewButtonWithIcon("Run", theme.MediaPlayIcon(), runSelectedFlow)
+	refreshFlowOptions()
+
+	// JSONata search UI
+	jsonataOutput := widget.NewMultiLineEntry()
+	jsonataOutput.SetPlaceHolder("JSONata output will appear here...")
+	jsonataOutput.SetMinRowsVisible(30)
+	jsonResponse.Wrapping = fyne.TextWrapBreak
+	jsonataOutput.Enable()
+	// Errors are shown in a dialog when applied by hand, and in the output box when applied automatically
+	applyJSONPath := func(interactive bool) {
+		expr := jsonataEntry.Text
+		if expr == "" {
+			if interactive {
+				dialog.ShowInformation("No Expression", "Please enter a JSONata expression.", w)
+			}
+			return
+		}
+		showErr := func(err error) {
+			if interactive {
+				dialog.ShowError(err, w)
+			} else {
+				jsonataOutput.SetText(err.Error())
+			}
+		}
+		var jsonData interface{}
+		if err := json.Unmarshal([]byte(search.text), &jsonData); err != nil {
+			showErr(fmt.Errorf("Invalid JSON: %v", err))
+			return
+		}
+		res, err := jsonpath.Get(expr, jsonData)
+		if err != nil {
+			showErr(fmt.Errorf("JSONata error: %v", err))
+			return
+		}
+		resStr, _ := json.MarshalIndent(res, "", "  ")
+		jsonataOutput.SetText(string(resStr))
+		// Optionally, do not overwrite the main response box
+		// jsonResponse.SetText(string(resStr))
+	}
+	jsonataBtn := widget.NewButton("Apply JSONata", func() {
+		applyJSONPath(true)
+		// Remember the expression on the loaded saved request
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			return
+		}
+		req := workspaces[wsIdx].Collections[selectedCollectionIdx].request(selectedRequestIdx)
+		if req != nil && req.JSONPath != jsonataEntry.Text {
+			req.JSONPath = jsonataEntry.Text
+			_ = saveModel()
+		}
+	})
+	jsonataAutoCheck := widget.NewCheck("Apply on each response", func(checked bool) {
+		a.Preferences().SetBool(prefJSONPathAutoApply, checked)
+	})
+	jsonataAutoCheck.SetChecked(a.Preferences().Bool(prefJSONPathAutoApply))
+
+	// Unmodified bytes of the last response body, and its Content-Type
+	var lastResponseBody []byte
+	var lastContentType string
+	// Content-Disposition and URL of the last response, for naming saved bodies
+	var lastContentDisposition, lastResponseURL string
+	// Status code and response time of the last response, for the Tests tab
+	var lastStatusCode int
+	var lastElapsed time.Duration
+	// Pretty-printing applies to JSON content types only, unless the setting says to trust any valid JSON
+	prettyFor := func(contentType string) bool {
+		return prettyCheck.Checked && (isJSONContentType(contentType) || a.Preferences().Bool(prefPrettyAnyContentType))
+	}
+
+	// Preview tab: images are drawn scaled to fit and HTML is rendered as rich text;
+	// anything else gets a note instead of stale content
+	previewImage := canvas.NewImageFromImage(nil)
+	previewImage.FillMode = canvas.ImageFillContain
+	previewMessage := widget.NewLabel("Preview will appear here.")
+	previewMessage.Wrapping = fyne.TextWrapWord
+	previewRich := widget.NewRichText()
+	previewRich.Wrapping = fyne.TextWrapWord
+	previewRichScroll := container.NewVScroll(previewRich)
+	previewRichScroll.Hide()
+	// updatePreview renders the last response and returns a note for the response meta, e.g. "640×480 png"
+	updatePreview := func() string {
+		previewImage.Image = nil
+		previewImage.Hide()
+		previewRichScroll.Hide()
+		previewMessage.Show()
+		switch {
+		case len(lastResponseBody) == 0:
+			previewMessage.SetText("No response body to preview.")
+		case isImageContentType(lastContentType):
+			img, format, err := decodeImage(lastResponseBody)
+			if err != nil {
+				previewMessage.SetText(fmt.Sprintf("Could not decode the %s image: %v", lastContentType, err))
+				break
+			}
+			previewImage.Image = img
+			previewImage.Show()
+			previewImage.Refresh()
+			previewMessage.Hide()
+			size := img.Bounds().Size()
+			return fmt.Sprintf("%d×%d %s", size.X, size.Y, format)
+		case isHTMLContentType(lastContentType):
+			// Rendered loosely through Markdown; the JSON tab keeps the raw HTML
+			previewRich.ParseMarkdown(htmlToMarkdown(lastResponseBody))
+			previewRichScroll.ScrollToTop()
+			previewRichScroll.Show()
+			previewMessage.Hide()
+		default:
+			previewMessage.SetText(fmt.Sprintf("Not an image or HTML (Content-Type: %s); nothing to preview.", lastContentType))
+		}
+		return ""
+	}
+
+	// Shown above the response when it was cut off at the size limit
+	truncatedBanner := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	truncatedBanner.Wrapping = fyne.TextWrapWord
+	truncatedBanner.Hide()
+
+	// Reset response views and search state after a failed request
+	showSendError := func(msg string) {
+		truncatedBanner.Hide()
+		activity.add("Error: %s", strings.ReplaceAll(msg, "\n\n", " "))
+		lastResponseBody = nil
+		lastContentType = ""
+		showResponseText(msg)
+		// statusLabel.SetText("")
+		headersBox.SetText("")
+		rawHeadersBox.SetText("")
+		setResponseMeta("", thresholdOK)
+		updatePreview()
+	}
+
+	// The most recent exchange, kept for "Export interaction"
+	var lastInteraction *interaction
+
+	// Re-render the last response body after the pretty/wrap toggles change
+	renderResponseBody := func() {
+		if wrapCheck.Checked {
+			jsonResponse.Wrapping = fyne.TextWrapBreak
+		} else {
+			jsonResponse.Wrapping = fyne.TextWrapOff
+		}
+		if len(lastResponseBody) == 0 {
+			jsonResponse.Refresh()
+			return
+		}
+		showResponseText(formatResponseBody(lastResponseBody, prettyFor(lastContentType)))
+	}
+	prettyCheck.OnChanged = func(bool) {
+		formChanged()
+		renderResponseBody()
+	}
+
+	// Copy the response as single-line JSON, e.g. for pasting into code or logs
+	copyMinifiedBtn := widget.NewButtonWithIcon("Copy Minified", theme.ContentCopyIcon(), func() {
+		var compact bytes.Buffer
+		if len(lastResponseBody) == 0 || json.Compact(&compact, lastResponseBody) != nil {
+			dialog.ShowInformation("Not JSON", "The response body is not valid JSON.", w)
+			return
+		}
+		w.Clipboard().SetContent(compact.String())
+		dialog.ShowInformation("Copied", "Minified response copied to clipboard!", w)
+	})
+
+	// Go type definitions matching the JSON response, for pasting into client code
+	goStructBtn := widget.NewButtonWithIcon("Go Struct", theme.DocumentIcon(), func() {
+		code, err := generateGoStructs(lastResponseBody, "Response")
+		if err != nil {
+			dialog.ShowInformation("Not JSON", "The response body is not valid JSON.", w)
+			return
+		}
+		codeEntry := widget.NewMultiLineEntry()
+		codeEntry.SetText(code)
+		codeEntry.TextStyle = fyne.TextStyle{Monospace: true}
+		codeEntry.SetMinRowsVisible(20)
+		copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+			w.Clipboard().SetContent(codeEntry.Text)
+		})
+		d := dialog.NewCustom("Generated Go Struct", "Close",
+			container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), copyBtn), nil, nil, codeEntry), w)
+		d.Resize(fyne.NewSize(700, 550))
+		d.Show()
+	})
+
+	// Save the last response body, either byte-for-byte or as shown with Pretty on
+	const (
+		saveRawOption       = "Raw bytes (exactly as received)"
+		saveFormattedOption = "Formatted (pretty-printed JSON, may differ from the received bytes)"
+	)
+	saveResponseBtn := widget.NewButtonWithIcon("Save Response", theme.DocumentSaveIcon(), func() {
+		if len(lastResponseBody) == 0 {
+			dialog.ShowInformation("No Response", "Send a request first.", w)
+			return
+		}
+		body := lastResponseBody
+		formatOption := widget.NewRadioGroup([]string{saveRawOption, saveFormattedOption}, nil)
+		formatOption.SetSelected(saveRawOption)
+		if !json.Valid(body) {
+			formatOption.Disable()
+		}
+		dialog.ShowCustomConfirm("Save Response", "Save", "Cancel", container.NewVBox(
+			widget.NewLabel("Save the response body as:"),
+			formatOption,
+		), func(ok bool) {
+			if !ok {
+				return
+			}
+			data := body
+			fileName := responseFileName(lastContentType, lastContentDisposition, lastResponseURL, body)
+			if formatOption.Selected == saveFormattedOption {
+				data = []byte(formatResponseBody(body, true))
+			}
+			save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil || writer == nil {
+					return
+				}
+				defer writer.Close()
+				if _, err := writer.Write(data); err != nil {
+					dialog.ShowError(fmt.Errorf("Write error: %v", err), w)
+					return
+				}
+				activity.add("Saved response (%d bytes) to %s", len(data), writer.URI().Name())
+			}, w)
+			save.SetFileName(fileName)
+			save.Show()
+		}, w)
+	})
+	wrapCheck.OnChanged = func(bool) {
+		formChanged()
+		renderResponseBody()
+	}
+
+	// Evaluate the request's assertions against the last response and list pass/fail
+	testsResults := container.NewVBox()
+	runTests := func() {
+		testsResults.RemoveAll()
+		if lastResponseBody == nil {
+			return
+		}
+		for _, res := range evaluateAssertions(strings.Split(testsEntry.Text, "\n"), lastStatusCode, lastElapsed, lastResponseBody) {
+			mark := "❌"
+			if res.Passed {
+				mark = "✅"
+			}
+			testsResults.Add(widget.NewLabel(fmt.Sprintf("%s  %s    (%s)", mark, res.Assertion, res.Detail)))
+		}
+	}
+	testsEntry.OnChanged = func(string) {
+		formChanged()
+		runTests()
+	}
+
+	// Results of the extraction rules for the last response
+	extractResults := widget.NewLabel("")
+	extractResults.Wrapping = fyne.TextWrapWord
+	extractEntry.OnChanged = func(string) { formChanged() }
+	runExtraction := func(r APIRequest, header http.Header, body []byte) {
+		values, problems := extractValues(r.Extract, header, body)
+		lines := []string{}
+		if len(values) > 0 {
+			target, err := storeVariables(r, values)
+			if err != nil {
+				problems = append(problems, err.Error())
+			}
+			names := make([]string, 0, len(values))
+			for k := range values {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			for _, k := range names {
+				lines = append(lines, fmt.Sprintf("✅  %s = %s", k, values[k]))
+			}
+			activity.add("Extracted %s into %s", strings.Join(names, ", "), target)
+		}
+		for _, p := range problems {
+			lines = append(lines, "❌  "+p)
+		}
+		extractResults.SetText(strings.Join(lines, "\n"))
+	}
+
+	// Reshape the last response with the request's Transform mapping
+	transformOutput := widget.NewMultiLineEntry()
+	transformOutput.SetPlaceHolder("Transformed response will appear here...")
+	transformOutput.SetMinRowsVisible(12)
+	transformProblems := widget.NewLabel("")
+	transformProblems.Wrapping = fyne.TextWrapWord
+	runTransform := func() {
+		transformOutput.SetText("")
+		transformProblems.SetText("")
+		if lastResponseBody == nil || strings.TrimSpace(transformEntry.Text) == "" {
+			return
+		}
+		result, problems := applyTransform(strings.Split(transformEntry.Text, "\n"), lastResponseBody)
+		transformOutput.SetText(result)
+		transformProblems.SetText(strings.Join(problems, "\n"))
+	}
+	transformEntry.OnChanged = func(string) {
+		formChanged()
+		runTransform()
+	}
+
+	// Table view of the last response when it is an array of objects; row 0 holds the column names
+	var responseTable tableData
+	tableView := widget.NewTable(
+		func() (int, int) {
+			if len(responseTable.Columns) == 0 {
+				return 0, 0
+			}
+			return len(responseTable.Rows) + 1, len(responseTable.Columns)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(responseTable.Columns[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			label.SetText(responseTable.Rows[id.Row-1][id.Col])
+		},
+	)
+	tableUnavailable := widget.NewLabel("The response is not a JSON array of objects.")
+	updateTableView := func() {
+		data, ok := tabularJSON(lastResponseBody)
+		if !ok {
+			responseTable = tableData{}
+			tableView.Hide()
+			tableUnavailable.Show()
+			return
+		}
+		responseTable = data
+		for i := range data.Columns {
+			tableView.SetColumnWidth(i, 160)
+		}
+		tableUnavailable.Hide()
+		tableView.Show()
+		tableView.Refresh()
+		tableView.ScrollToTop()
+	}
+	updateTableView()
+
+	// Cancels the in-flight request; nil when nothing is being sent. The send goroutine
+	// clears it when done, so it is guarded by cancelMu, and cancelSeq tells which send
+	// set it so a finished send can't clear the cancel of the one queued after it.
+	var cancelMu sync.Mutex
+	var cancelSend context.CancelFunc
+	cancelSeq := 0
+	// stopSend cancels the in-flight request, reporting whether there was one
+	stopSend := func() bool {
+		cancelMu.Lock()
+		defer cancelMu.Unlock()
+		if cancelSend == nil {
+			return false
+		}
+		cancelSend()
+		return true
+	}
+	isSending := func() bool {
+		cancelMu.Lock()
+		defer cancelMu.Unlock()
+		return cancelSend != nil
+	}
+	// While a request runs the Send button turns into Stop
+	showStopButton := func(running bool) {
+		if running {
+			sendBtn.SetText("Stop")
+			sendBtn.Importance = widget.DangerImportance
+		} else {
+			sendBtn.SetText("Send")
+			sendBtn.Importance = widget.HighImportance
+		}
+		sendBtn.Refresh()
+	}
+	// Escape cancels too; sending drops keyboard focus so the key reaches the window
+	w.Canvas().SetOnTypedKey(func(ev *fyne.KeyEvent) {
+		if ev.Name == fyne.KeyEscape {
+			stopSend()
+		}
+	})
+
+	// Sends run one at a time in the order they were issued, each with the form as it was
+	// when Send was pressed; a queued send starts only after the previous response has
+	// rendered. Stop and Escape cancel the running send, queued ones still go out.
+	type queuedSend struct {
+		r      APIRequest
+		onDone func(statusCode int)
+	}
+	var sendMu sync.Mutex
+	sending := false
+	sendQueue := []queuedSend{}
+	queueLabel := widget.NewLabel("")
+	queueLabel.Hide()
+	// Must be called with sendMu held
+	updateQueueLabel := func() {
+		if len(sendQueue) == 0 {
+			queueLabel.Hide()
+			return
+		}
+		queueLabel.SetText(fmt.Sprintf("%d queued", len(sendQueue)))
+		queueLabel.Show()
+	}
+	var startSend func(r APIRequest, onDone func(statusCode int))
+	// Start the oldest queued send, or mark the sender idle
+	nextSend := func() {
+		sendMu.Lock()
+		if len(sendQueue) == 0 {
+			sending = false
+			updateQueueLabel()
+			sendMu.Unlock()
+			return
+		}
+		next := sendQueue[0]
+		sendQueue = sendQueue[1:]
+		updateQueueLabel()
+		sendMu.Unlock()
+		startSend(next.r, next.onDone)
+	}
+
+	// onDone, if set, is called with the status code once a response has been received
+	doSend := func(r APIRequest, onDone func(statusCode int)) {
+		sendMu.Lock()
+		if sending {
+			sendQueue = append(sendQueue, queuedSend{r: r, onDone: onDone})
+			updateQueueLabel()
+			sendMu.Unlock()
+			return
+		}
+		sending = true
+		sendMu.Unlock()
+		startSend(r, onDone)
+	}
+
+	responses := newResponseCache()
+
+	startSend = func(r APIRequest, onDone func(statusCode int)) {
+		req, reqSize, err := buildHTTPRequest(r)
+		if err != nil {
+			showSendError(fmt.Sprintf("Request error: %v", err))
+			nextSend()
+			return
+		}
+		if r.ConditionalRequests {
+			if cached, ok := responses.get(cacheKey(r)); ok {
+				applyConditionalHeaders(req, cached)
+			}
+		}
+		activity.add("Sent %s %s", req.Method, req.URL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req = req.WithContext(ctx)
+		cancelMu.Lock()
+		cancelSeq++
+		seq := cancelSeq
+		cancelSend = cancel
+		cancelMu.Unlock()
+		showStopButton(true)
+		w.Canvas().Unfocus()
+
+		// Run the request off the UI goroutine so the progress bar can update while the body streams in
+		downloadedBytes.Store(0)
+		downloadProgress.SetValue(0)
+		downloadProgress.Show()
+		// Count down to the timeout; the progress bar only refreshes on reads otherwise
+		sendDeadline.Store(0)
+		stopCountdown := func() {}
+		if r.TimeoutSeconds > 0 {
+			sendDeadline.Store(time.Now().Add(time.Duration(r.TimeoutSeconds) * time.Second).UnixNano())
+			ticker := time.NewTicker(250 * time.Millisecond)
+			stop := make(chan struct{})
+			stopCountdown = func() {
+				ticker.Stop()
+				close(stop)
+			}
+			go func() {
+				for {
+					select {
+					case <-ticker.C:
+						downloadProgress.Refresh()
+					case <-stop:
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			defer func() {
+				stopCountdown()
+				sendDeadline.Store(0)
+				cancel()
+				cancelMu.Lock()
+				if cancelSeq == seq {
+					cancelSend = nil
+				}
+				cancelMu.Unlock()
+				showStopButton(false)
+				downloadProgress.Hide()
+				nextSend()
+			}()
+			client := newHTTPClient(r, currentCookieJar())
+			var rawHeaders *rawHeaderRecorder
+			if captureRawHeadersCheck.Checked {
+				rawHeaders = recordRawHeaders(client, req)
+				if rawHeaders != nil {
+					defer client.CloseIdleConnections()
+				}
+			}
+			startTime := time.Now()
+			resp, err := doWithRetries(client, req, r.Retries, func() (*http.Request, error) {
+				retry, _, err := buildHTTPRequest(r)
+				if err != nil {
+					return nil, err
+				}
+				if r.ConditionalRequests {
+					if cached, ok := responses.get(cacheKey(r)); ok {
+						applyConditionalHeaders(retry, cached)
+					}
+				}
+				activity.add("Retrying %s %s", retry.Method, retry.URL)
+				return retry.WithContext(ctx), nil
+			})
+			if err != nil {
+				if ctx.Err() == context.Canceled {
+					showSendError("Request cancelled.")
+					return
+				}
+				if isTimeoutError(err) && r.TimeoutSeconds > 0 {
+					showSendError(fmt.Sprintf("Request timed out after %ds (elapsed %d ms) without a response.\n\n"+
+						"The server is slow or unreachable; raise the timeout in the request's Settings tab.",
+						r.TimeoutSeconds, time.Since(startTime).Milliseconds()))
+					return
+				}
+				msg := fmt.Sprintf("HTTP error: %v", err)
+				if hint := errorHint(err); hint != "" {
+					msg = hint + "\n\n" + msg
+				}
+				showSendError(msg)
+				return
+			}
+			defer resp.Body.Close()
+			total := resp.ContentLength
+			lastUpdate := time.Time{}
+			maxMB := a.Preferences().IntWithFallback(prefMaxResponseMB, defaultMaxResponseMB)
+			respBody, truncated, err := readLimited(resp.Body, int64(maxMB)*1024*1024, func(read int64) {
+				downloadedBytes.Store(read)
+				// Throttle widget refreshes; large bodies produce many small reads
+				if time.Since(lastUpdate) < 50*time.Millisecond {
+					return
+				}
+				lastUpdate = time.Now()
+				if total > 0 {
+					downloadProgress.SetValue(float64(read) / float64(total))
+				} else {
+					downloadProgress.Refresh()
+				}
+			})
+			elapsed := time.Since(startTime)
+			// A timeout mid-body keeps what arrived so far; partial responses often show where it stalled
+			timedOut := false
+			if err != nil {
+				if ctx.Err() == context.Canceled {
+					showSendError("Request cancelled.")
+					return
+				}
+				if !isTimeoutError(err) || len(respBody) == 0 {
+					if isTimeoutError(err) && r.TimeoutSeconds > 0 {
+						showSendError(fmt.Sprintf("Request timed out after %ds (elapsed %d ms) before any of the body arrived.",
+							r.TimeoutSeconds, elapsed.Milliseconds()))
+						return
+					}
+					showSendError(fmt.Sprintf("Read error: %v", err))
+					return
+				}
+				timedOut = true
+			}
+			// On 304 show the body of the response the validators came from
+			var cachedAt time.Time
+			contentType := resp.Header.Get("Content-Type")
+			if r.ConditionalRequests {
+				if resp.StatusCode == http.StatusNotModified {
+					if cached, ok := responses.get(cacheKey(r)); ok {
+						respBody = cached.Body
+						cachedAt = cached.Stored
+						contentType = cached.ContentType
+					}
+				} else if resp.StatusCode >= 200 && resp.StatusCode < 300 && !truncated && !timedOut {
+					responses.store(cacheKey(r), resp.Header, respBody)
+				}
+			}
+			if len(respBody) == 0 {
+				// Say so explicitly, an empty box looks like a failure
+				showResponseText(emptyBodyMessage(req.Method, resp.StatusCode, resp.Status))
+			} else {
+				showResponseText(formatResponseBody(respBody, prettyFor(contentType)))
+			}
+			// Status label (for headers panel)
+			// statusLabel.SetText(fmt.Sprintf("Status: %d %s", resp.StatusCode, resp.Status))
+			// Format response headers, after any redirects that led to them
+			headersStr := describeRedirects(resp)
+			for k, v := range resp.Header {
+				headersStr += fmt.Sprintf("%s: %s\n", k, strings.Join(v, ", "))
+			}
+			headersBox.SetText(headersStr)
+			switch {
+			case !captureRawHeadersCheck.Checked:
+				rawHeadersBox.SetText("")
+			case rawHeaders == nil:
+				rawHeadersBox.SetText("Raw headers can't be captured for requests sent through a proxy.")
+			default:
+				rawHeadersBox.SetText(strings.ReplaceAll(rawHeaders.final(), "\r\n", "\n"))
+			}
+			// Set response meta info
+			respSize := len(respBody)
+			lastResponseBody = respBody
+			lastContentType = contentType
+			lastContentDisposition = resp.Header.Get("Content-Disposition")
+			lastResponseURL = req.URL.String()
+			lastStatusCode, lastElapsed = resp.StatusCode, elapsed
+			runExtraction(r, resp.Header, respBody)
+			runTests()
+			runTransform()
+			updateTableView()
+			previewInfo := updatePreview()
+			if jsonataAutoCheck.Checked {
+				applyJSONPath(false)
+			}
+			sentBody := r.Body
+			switch r.BodyMode {
+			case bodyModeFile:
+				sentBody = "(contents of " + r.BodyFile + ")"
+			case bodyModeMultipart:
+				sentBody = "(multipart form)\n" + strings.Join(formFieldLines(r.FormFields), "\n")
+			case bodyModeURLEncoded:
+				sentBody = encodeFormFields(r.FormFields)
+			}
+			lastInteraction = &interaction{
+				Timestamp: startTime,
+				Request: interactionRequest{
+					Method:  req.Method,
+					URL:     req.URL.String(),
+					Host:    r.Host,
+					Headers: req.Header,
+					Body:    sentBody,
+				},
+				Response: interactionResponse{
+					Status:     resp.Status,
+					StatusCode: resp.StatusCode,
+					Headers:    resp.Header,
+					Body:       string(respBody),
+					DurationMs: elapsed.Milliseconds(),
+					Size:       respSize,
+				},
+			}
+			// Flag slow or large responses against the configured thresholds
+			slowMs := a.Preferences().IntWithFallback(prefSlowResponseMs, defaultSlowResponseMs)
+			largeKB := a.Preferences().IntWithFallback(prefLargeResponseKB, defaultLargeResponseKB)
+			level := thresholdLevel(float64(elapsed.Milliseconds()), float64(slowMs))
+			if sizeLevel := thresholdLevel(float64(respSize), float64(largeKB)*1024); sizeLevel > level {
+				level = sizeLevel
+			}
+			activity.add("Received %s from %s %s in %d ms (%s)", resp.Status, req.Method, req.URL, elapsed.Milliseconds(), formatSize(respSize))
+			meta := fmt.Sprintf("%d ms    Req: %s    Resp: %s",
+				elapsed.Milliseconds(),
+				formatSize(reqSize),
+				formatSize(respSize),
+			)
+			if previewInfo != "" {
+				meta += "    Image: " + previewInfo
+			}
+			if timedOut {
+				meta += " (timed out)"
+				truncatedBanner.SetText(fmt.Sprintf("⏱ Timed out after %d s having received %s; showing the partial body.", r.TimeoutSeconds, formatSize(respSize)))
+				truncatedBanner.Show()
+				level = thresholdFarExceeded
+			} else if truncated {
+				meta += " (truncated)"
+				truncatedBanner.SetText(fmt.Sprintf("⚠ Response truncated at %d MB; this is not the full body. Raise the limit in Settings to see all of it.", maxMB))
+				truncatedBanner.Show()
+				level = thresholdFarExceeded
+			} else {
+				truncatedBanner.Hide()
+			}
+			setResponseMeta(meta, level)
+			// Status code indicator with emoji and text
+			fill, statusText := statusIndicator(resp.StatusCode)
+			if len(r.ExpectedStatus) > 0 {
+				if statusExpected(resp.StatusCode, r.ExpectedStatus) {
+					fill = statusGreen
+					statusText = "PASS  " + statusText
+				} else {
+					fill = statusRed
+					statusText = fmt.Sprintf("FAIL (expected %s)  %s", formatStatusCodes(r.ExpectedStatus), statusText)
+				}
+			}
+			statusColor.FillColor = fill
+			if !cachedAt.IsZero() {
+				statusText += fmt.Sprintf(" (showing cached body from %s)", cachedAt.Format("15:04:05"))
+			}
+			statusColor.Refresh()
+			responseStatus.SetText(statusText)
+			responseStatus.Refresh()
+			if tab := a.Preferences().StringWithFallback(prefDefaultResponseTab, keepCurrentTab); tab != keepCurrentTab {
+				for _, item := range responseTabs.Items {
+					if item.Text == tab {
+						responseTabs.Select(item)
+					}
+				}
+			}
+			if onDone != nil {
+				onDone(resp.StatusCode)
+			}
+		}()
+	}
+
+	// composeFromForm turns the form into the request that would be sent, plus any variable problems
+	composeFromForm := func() (form, r APIRequest, issues []string) {
+		// Resolve {{var}} references from the active (or overriding) environment before sending
+		form = formRequest()
+		vars := formVariables(form)
+		// Global headers, the default timeout and the proxy/TLS settings are applied at send time so they never end up saved on the request
+		r = resolveRequest(withGlobalHeaders(form, parseHeaders(a.Preferences().String(prefGlobalHeaders))), vars)
+		r = withDefaultTimeout(r, a.Preferences().IntWithFallback(prefDefaultTimeoutSeconds, defaultTimeoutSeconds))
+		r = withConnectionDefaults(r, proxySetting(), a.Preferences().Bool(prefInsecureSkipVerify))
+		authTexts := []string{}
+		if form.Auth != nil {
+			authTexts = append(authTexts, form.Auth.Token, form.Auth.Username, form.Auth.Password)
+		}
+		issues = lintVariables(vars, append([]string{form.URL, headersToText(form.Headers), form.Body, form.Host}, authTexts...)...)
+		return form, r, issues
+	}
+
+	sendFromForm := func(onDone func(statusCode int)) {
+		form, r, issues := composeFromForm()
+		names := []string{}
+		for k := range form.Headers {
+			names = append(names, k)
 		}
+		a.Preferences().SetStringList(prefRecentHeaders, rememberHeaders(a.Preferences().StringList(prefRecentHeaders), names))
 
-		coll := &workspaces[wsIdx].Collections[selectedCollectionIdx]
-		if reqIdx >= len(coll.Requests) {
+		if len(issues) == 0 {
+			doSend(r, onDone)
 			return
 		}
-
-		reqName := coll.Requests[reqIdx].Name
-		dialog.ShowConfirm("Delete Request",
-			fmt.Sprintf("Are you sure you want to delete the request '%s'?", reqName),
+		dialog.ShowConfirm("Variable Problems",
+			"The request uses variables that may not resolve as expected:\n\n"+
+				strings.Join(issues, "\n")+"\n\nSend anyway?",
 			func(confirmed bool) {
 				if confirmed {
-					// Remove the request from the slice
-					coll.Requests = append(coll.Requests[:reqIdx], coll.Requests[reqIdx+1:]...)
-					err := saveWorkspaces(workspaces)
-					if err == nil {
-						requestList.Refresh()
-					}
+					doSend(r, onDone)
 				}
 			}, w)
 	}
-
-	// Create workspace dropdown
-	workspaceNames := []string{"+ New Workspace"}
-	for _, ws := range workspaces {
-		workspaceNames = append(workspaceNames, ws.Name)
+	sendBtn.OnTapped = func() {
+		if stopSend() {
+			return
+		}
+		sendFromForm(nil)
 	}
-	workspaceSelect = widget.NewSelect(workspaceNames, nil)
 
-	// Collection dropdown
-	collectionSelect = widget.NewSelect([]string{"+ New Collection"}, nil)
+	// Find out which methods the URL accepts; methods that may change data are only tried when asked
+	probeBtn := widget.NewButtonWithIcon("Probe Methods", theme.SearchIcon(), func() {
+		_, r, _ := composeFromForm()
+		if strings.TrimSpace(r.URL) == "" {
+			dialog.ShowInformation("No URL", "Enter a URL to probe.", w)
+			return
+		}
+		unsafeCheck := widget.NewCheck("Also try "+strings.Join(unsafeProbeMethods, ", ")+" (sent without a body; the server may act on them)", nil)
+		content := container.NewVBox(
+			widget.NewLabel("Sends OPTIONS, "+strings.Join(safeProbeMethods, " and ")+" to\n"+r.URL),
+			unsafeCheck,
+		)
+		dialog.ShowCustomConfirm("Probe Methods", "Probe", "Cancel", content, func(ok bool) {
+			if !ok {
+				return
+			}
+			progress := dialog.NewCustomWithoutButtons("Probing...", widget.NewProgressBarInfinite(), w)
+			progress.Show()
+			go func() {
+				report := probeMethods(r, unsafeCheck.Checked, currentCookieJar())
+				progress.Hide()
+				activity.add("Probed methods of %s", r.URL)
+				result := widget.NewLabelWithStyle(report.summary(), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+				dialog.ShowCustom("Methods for "+r.URL, "Close", result, w)
+			}()
+		}, w)
+	})
 
-	// Request list for selected collection with edit/delete functionality
-	requestList = widget.NewList(
-		func() int {
-			if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" || selectedCollectionIdx < 0 {
-				return 0
+	// Dry run: compose the request exactly as Send would and show it, without sending anything
+	validateBtn := widget.NewButtonWithIcon("Validate", theme.ConfirmIcon(), func() {
+		_, r, issues := composeFromForm()
+		lines := []string{}
+		req, _, err := buildHTTPRequest(r)
+		if err != nil {
+			lines = append(lines, "❌ "+err.Error())
+		} else {
+			lines = append(lines, "✅ The request is well-formed.")
+		}
+		for _, issue := range issues {
+			lines = append(lines, "⚠ "+issue)
+		}
+		if !methodSendsBody(r.Method) && strings.TrimSpace(r.Body) != "" {
+			lines = append(lines, fmt.Sprintf("⚠ %s requests are sent without a body.", r.Method))
+		}
+		raw := ""
+		if req != nil {
+			if r.ConditionalRequests {
+				if cached, ok := responses.get(cacheKey(r)); ok {
+					applyConditionalHeaders(req, cached)
+				}
 			}
-			for _, ws := range workspaces {
-				if ws.Name == workspaceSelect.Selected {
-					if selectedCollectionIdx < len(ws.Collections) {
-						return len(ws.Collections[selectedCollectionIdx].Requests)
-					}
+			// Bodies that may contain files are left out so a large upload isn't read just to be displayed
+			withBody := r.BodyMode != bodyModeFile && r.BodyMode != bodyModeMultipart
+			dump, err := httputil.DumpRequestOut(req, withBody)
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			if err != nil {
+				lines = append(lines, "❌ "+err.Error())
+			} else {
+				raw = strings.ReplaceAll(string(dump), "\r\n", "\n")
+				if !withBody {
+					raw += fmt.Sprintf("(body streamed from %s)", r.BodyFile)
 				}
 			}
-			return 0
-		},
-		func() fyne.CanvasObject {
-			// Create a container with request name, edit button, and delete button
-			nameLabel := widget.NewLabel("")
-			editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil)
-			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+		}
+		rawView := widget.NewMultiLineEntry()
+		rawView.Wrapping = fyne.TextWrapBreak
+		rawView.SetText(raw)
+		summary := widget.NewLabel(strings.Join(lines, "\n"))
+		summary.Wrapping = fyne.TextWrapWord
+		d := dialog.NewCustom("Validate Request (not sent)", "Close", container.NewBorder(summary, nil, nil, nil, rawView), w)
+		d.Resize(fyne.NewSize(800, 550))
+		d.Show()
+	})
 
-			editBtn.Resize(fyne.NewSize(24, 24))
-			deleteBtn.Resize(fyne.NewSize(24, 24))
+	// Request tabs: the form and the response area show the active tab, and the
+	// others keep a copy of theirs until they are selected again
+	// The form of a blank request, what an unsaved tab is compared with
+	blankRequest := formRequest()
+	tabs := []*requestTab{{Collection: -1, Request: -1, Baseline: blankRequest}}
+	activeTab := 0
+	shownDirty := false
+	tabBar := container.NewHBox()
+	var refreshTabBar func()
 
-			return container.NewBorder(nil, nil, nil,
-				container.NewHBox(editBtn, deleteBtn),
-				nameLabel)
-		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			// Cast to fyne.Container instead of container.Border
-			containerObj := o.(*fyne.Container)
+	captureResponse := func() responseSnapshot {
+		s := responseSnapshot{
+			Body:               lastResponseBody,
+			ContentType:        lastContentType,
+			ContentDisposition: lastContentDisposition,
+			URL:                lastResponseURL,
+			StatusCode:         lastStatusCode,
+			Elapsed:            lastElapsed,
+			Text:               search.text,
+			Headers:            headersBox.Text,
+			RawHeaders:         rawHeadersBox.Text,
+			Status:             responseStatus.Text,
+			StatusFill:         statusColor.FillColor,
+			Meta:               responseMeta.Text,
+			MetaColor:          responseMeta.Color,
+			Extracted:          extractResults.Text,
+			JSONPath:           jsonataOutput.Text,
+			Interaction:        lastInteraction,
+		}
+		if truncatedBanner.Visible() {
+			s.Banner = truncatedBanner.Text
+		}
+		return s
+	}
+	restoreResponse := func(s responseSnapshot) {
+		lastResponseBody, lastContentType = s.Body, s.ContentType
+		lastContentDisposition, lastResponseURL = s.ContentDisposition, s.URL
+		lastStatusCode, lastElapsed = s.StatusCode, s.Elapsed
+		lastInteraction = s.Interaction
+		showResponseText(s.Text)
+		headersBox.SetText(s.Headers)
+		rawHeadersBox.SetText(s.RawHeaders)
+		responseStatus.SetText(s.Status)
+		statusColor.FillColor = s.StatusFill
+		if s.StatusFill == nil {
+			statusColor.FillColor = &color.NRGBA{0, 0, 0, 255}
+		}
+		statusColor.Refresh()
+		responseMeta.Text = s.Meta
+		responseMeta.Color = s.MetaColor
+		if s.MetaColor == nil {
+			responseMeta.Color = theme.ForegroundColor()
+		}
+		responseMeta.Refresh()
+		if s.Banner != "" {
+			truncatedBanner.SetText(s.Banner)
+			truncatedBanner.Show()
+		} else {
+			truncatedBanner.Hide()
+		}
+		extractResults.SetText(s.Extracted)
+		jsonataOutput.SetText(s.JSONPath)
+		runTests()
+		runTransform()
+		updateTableView()
+		updatePreview()
+	}
 
-			// In a border container, the main object is at index 0, and the trailing object (buttons) is at index 1
-			nameLabel := containerObj.Objects[0].(*widget.Label)
-			buttonContainer := containerObj.Objects[1].(*fyne.Container)
-			editBtn := buttonContainer.Objects[0].(*widget.Button)
-			deleteBtn := buttonContainer.Objects[1].(*widget.Button)
+	// A tab has unsaved changes when its form differs from the saved request,
+	// unless auto-save is writing the changes through
+	tabDirty := func(t *requestTab) bool {
+		draft := t.Draft
+		if t == tabs[activeTab] {
+			draft = formRequest()
+		}
+		if t.Request >= 0 && a.Preferences().Bool(prefAutoSave) {
+			return false
+		}
+		return !sameRequest(draft, t.Baseline)
+	}
+	updateTabDirty = func() {
+		if loadingForm {
+			return
+		}
+		if dirty := tabDirty(tabs[activeTab]); dirty != shownDirty {
+			refreshTabBar()
+		}
+	}
 
-			for _, ws := range workspaces {
-				if ws.Name == workspaceSelect.Selected {
-					if selectedCollectionIdx < len(ws.Collections) {
-						requests := ws.Collections[selectedCollectionIdx].Requests
-						if i < len(requests) {
-							nameLabel.SetText(requests[i].Name)
-
-							// Set up edit button callback (capture i in closure)
-							reqIdx := i
-							editBtn.OnTapped = func() {
-								editRequestName(reqIdx)
-							}
-
-							// Set up delete button callback (capture i in closure)
-							deleteBtn.OnTapped = func() {
-								deleteRequest(reqIdx)
-							}
-						}
-					}
-				}
-			}
-		},
-	)
+	// Point the request list selection, and so auto-save, at the tab's saved request
+	linkSelection := func(t *requestTab) {
+		selectingTab = true
+		defer func() { selectingTab = false }()
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx != -1 && t.isSaved(workspaces[wsIdx].Name, selectedCollectionIdx, t.Request) {
+			selectedRequestIdx = t.Request
+			revealRequest(t.Request)
+			return
+		}
+		selectedRequestIdx = -1
+		requestTree.UnselectAll()
+	}
 
-	// Set up click handler to load request
-	requestList.OnSelected = func(id int) {
-		// Load the request into the form
-		for _, ws := range workspaces {
-			if ws.Name == workspaceSelect.Selected {
-				if selectedCollectionIdx < len(ws.Collections) {
-					requests := ws.Collections[selectedCollectionIdx].Requests
-					if id < len(requests) {
-						r := requests[id]
-						methodSelect.SetSelected(r.Method)
-						urlEntry.SetText(r.URL)
-						headersEntry.SetText("")
-						for k, v := range r.Headers {
-							headersEntry.SetText(headersEntry.Text + k + ": " + v + "\n")
-						}
-						bodyEntry.SetText(r.Body)
-					}
-				}
-			}
+	// A response arriving after a switch would land in the wrong tab
+	sendInProgress := func() bool {
+		if isSending() {
+			dialog.ShowInformation("Request in Progress", "Wait for the request to finish, or stop it, before changing tabs.", w)
+			return true
 		}
+		return false
 	}
 
-	// Set up workspace selection callback after all widgets are created
-	workspaceSelect.OnChanged = func(selected string) {
-		if selected == "+ New Workspace" {
-			createNewWorkspace()
+	leaveActiveTab := func() {
+		tabs[activeTab].Draft = formRequest()
+		tabs[activeTab].Response = captureResponse()
+	}
+	showTab := func(i int) {
+		activeTab = i
+		loadRequestIntoForm(tabs[i].Draft)
+		updateBodyWarning()
+		restoreResponse(tabs[i].Response)
+		linkSelection(tabs[i])
+		refreshTabBar()
+	}
+	switchTab := func(i int) {
+		if i == activeTab || sendInProgress() {
+			linkSelection(tabs[activeTab])
 			return
 		}
-		// Update collection dropdown when workspace changes
-		selectedCollectionIdx = -1
-		collectionOptions := []string{"+ New Collection"}
-		for _, ws := range workspaces {
-			if ws.Name == selected {
-				for _, col := range ws.Collections {
-					collectionOptions = append(collectionOptions, col.Name)
-				}
-				break
-			}
-		}
-		collectionSelect.Options = collectionOptions
-		collectionSelect.SetSelected("")
-		requestList.Refresh()
+		leaveActiveTab()
+		showTab(i)
 	}
 
-	// Set up collection selection callback
-	collectionSelect.OnChanged = func(selected string) {
-		if selected == "+ New Collection" {
-			createNewCollection()
+	// addTab opens r in a new tab, or in the active tab when that is an untouched blank one
+	addTab := func(t *requestTab, r APIRequest) {
+		if sendInProgress() {
+			linkSelection(tabs[activeTab])
 			return
 		}
-		// Find the collection index
-		selectedCollectionIdx = -1
-		for _, ws := range workspaces {
-			if ws.Name == workspaceSelect.Selected {
-				for i, col := range ws.Collections {
-					if col.Name == selected {
-						selectedCollectionIdx = i
-						break
-					}
-				}
-				break
+		current := tabs[activeTab]
+		if current.Request >= 0 || tabDirty(current) || search.text != "" {
+			leaveActiveTab()
+			tabs = append(tabs, t)
+			activeTab = len(tabs) - 1
+		} else {
+			tabs[activeTab] = t
+		}
+		t.Draft = r
+		t.Draft.Name = r.URL
+		showTab(activeTab)
+		t.Baseline = blankRequest
+		if t.Request >= 0 {
+			t.Baseline = formRequest()
+		}
+		refreshTabBar()
+	}
+	openRequestTab = func(reqIdx int, r APIRequest) {
+		for i, t := range tabs {
+			if t.isSaved(workspaceSelect.Selected, selectedCollectionIdx, reqIdx) {
+				switchTab(i)
+				return
 			}
 		}
-		requestList.Refresh()
+		addTab(&requestTab{Workspace: workspaceSelect.Selected, Collection: selectedCollectionIdx, Request: reqIdx, Title: r.Name}, r)
 	}
-
-	if len(workspaceNames) > 1 {
-		workspaceSelect.SetSelected(workspaceNames[1]) // Select first actual workspace, not "+ New Workspace"
+	// Open a blank, unsaved GET in a new tab; Save Request adds it to the current collection
+	newTab := func() {
+		addTab(&requestTab{Collection: -1, Request: -1}, newAPIRequest("GET", ""))
+		saveIndicator.SetText("New request (unsaved)")
+		w.Canvas().Focus(urlEntry)
 	}
 
-	// Initialize collection options for the selected workspace
-	if workspaceSelect.Selected != "" && workspaceSelect.Selected != "+ New Workspace" {
-		collectionOptions := []string{"+ New Collection"}
-		for _, ws := range workspaces {
-			if ws.Name == workspaceSelect.Selected {
-				for _, col := range ws.Collections {
-					collectionOptions = append(collectionOptions, col.Name)
+	closeTab := func(t *requestTab) {
+		closeNow := func() {
+			i := -1
+			for j, other := range tabs {
+				if other == t {
+					i = j
 				}
-				break
+			}
+			if i == -1 {
+				return
+			}
+			if i == activeTab && sendInProgress() {
+				return
+			}
+			if len(tabs) == 1 {
+				// The last tab is replaced by a blank one rather than leaving no form
+				tabs[0] = &requestTab{Collection: -1, Request: -1, Draft: newAPIRequest("GET", ""), Baseline: blankRequest}
+				showTab(0)
+				return
+			}
+			tabs = append(tabs[:i], tabs[i+1:]...)
+			switch {
+			case i == activeTab:
+				if i == len(tabs) {
+					i--
+				}
+				showTab(i)
+			case i < activeTab:
+				activeTab--
+				refreshTabBar()
+			default:
+				refreshTabBar()
 			}
 		}
-		collectionSelect.Options = collectionOptions
+		if !tabDirty(t) {
+			closeNow()
+			return
+		}
+		title := t.Title
+		if title == "" {
+			title = "New Request"
+		}
+		dialog.ShowConfirm("Unsaved Changes", fmt.Sprintf("'%s' has unsaved changes. Close it anyway?", title), func(ok bool) {
+			if ok {
+				closeNow()
+			}
+		}, w)
 	}
 
-	// Flows canvas placeholder
-	flowsLabel := widget.NewLabel("Flows canvas: Drag and chain API calls here (future)")
+	refreshTabBar = func() {
+		tabBar.RemoveAll()
+		for i, t := range tabs {
+			i, t := i, t
+			label := t.Title
+			if label == "" {
+				label = "New Request"
+			}
+			dirty := tabDirty(t)
+			if dirty {
+				label = "● " + label
+			}
+			if i == activeTab {
+				shownDirty = dirty
+			}
+			tab := newTabButton(label, func() { switchTab(i) }, func() { closeTab(t) })
+			if i == activeTab {
+				tab.Importance = widget.HighImportance
+			}
+			closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() { closeTab(t) })
+			closeBtn.Importance = widget.LowImportance
+			tabBar.Add(container.NewHBox(tab, closeBtn))
+		}
+		tabBar.Add(widget.NewButtonWithIcon("", theme.ContentAddIcon(), newTab))
+	}
+	refreshTabBar()
 
-	// JSONata search UI
-	jsonataEntry := widget.NewEntry()
-	jsonataEntry.SetPlaceHolder("Enter JSONata expression (e.g. $.foo.bar)")
-	jsonataOutput := widget.NewMultiLineEntry()
-	jsonataOutput.SetPlaceHolder("JSONata output will appear here...")
-	jsonataOutput.SetMinRowsVisible(30)
-	jsonResponse.Wrapping = fyne.TextWrapBreak
-	jsonataOutput.Enable()
-	jsonataBtn := widget.NewButton("Apply JSONata", func() {
-		expr := jsonataEntry.Text
-		if expr == "" {
-			dialog.ShowInformation("No Expression", "Please enter a JSONata expression.", w)
-			return
+	// Link the active tab to a request just saved from it
+	markTabSaved := func(reqIdx int, req APIRequest) {
+		t := tabs[activeTab]
+		t.Workspace, t.Collection, t.Request, t.Title = workspaceSelect.Selected, selectedCollectionIdx, reqIdx, req.Name
+		t.Baseline = req
+		t.Baseline.Name = req.URL
+		linkSelection(t)
+		refreshTabBar()
+	}
+	// Tabs of count deleted requests from index first become unsaved; later requests
+	// of their collection move up
+	requestsRemoved = func(first, count int) {
+		for _, t := range tabs {
+			if t.Workspace != workspaceSelect.Selected || t.Collection != selectedCollectionIdx || t.Request < first {
+				continue
+			}
+			if t.Request < first+count {
+				t.Request = -1
+			} else {
+				t.Request -= count
+			}
 		}
-		var jsonData interface{}
-		jsonText := originalText
-		if jsonText == "" {
-			jsonText = jsonResponse.Text
+		refreshTabBar()
+	}
+
+	// Tabs of a moved request follow it; later requests of its old collection move up
+	// one and those from its new index on in the new collection move down one
+	requestMoved = func(reqIdx int, toWorkspace string, toCollection, toRequest int) {
+		moved := []*requestTab{}
+		for _, t := range tabs {
+			if t.isSaved(workspaceSelect.Selected, selectedCollectionIdx, reqIdx) {
+				moved = append(moved, t)
+			} else if t.Request > reqIdx && t.Workspace == workspaceSelect.Selected && t.Collection == selectedCollectionIdx {
+				t.Request--
+			}
 		}
-		if err := json.Unmarshal([]byte(jsonText), &jsonData); err != nil {
-			dialog.ShowError(fmt.Errorf("Invalid JSON: %v", err), w)
-			return
+		for _, t := range tabs {
+			if t.Request >= toRequest && t.Workspace == toWorkspace && t.Collection == toCollection {
+				t.Request++
+			}
 		}
-		res, err := jsonpath.Get(expr, jsonData)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("JSONata error: %v", err), w)
-			return
+		for _, t := range moved {
+			t.Workspace, t.Collection, t.Request = toWorkspace, toCollection, toRequest
 		}
-		resStr, _ := json.MarshalIndent(res, "", "  ")
-		jsonataOutput.SetText(string(resStr))
-		// Optionally, do not overwrite the main response box
-		// jsonResponse.SetText(string(resStr))
-		// Reset search state after applying JSONata
-		originalText = jsonResponse.Text
-		currentSearchQuery = ""
-		searchResults = []int{}
-		currentMatchIndex = -1
-		updateSearchNav()
-	})
+		linkSelection(tabs[activeTab])
+		refreshTabBar()
+	}
 
-	sendBtn.OnTapped = func() {
-		method := methodSelect.Selected
-		url := urlEntry.Text
-		headers := parseHeaders(headersEntry.Text)
-		body := bodyEntry.Text
-
-		var req *http.Request
-		var err error
-		var reqSize int
-		if method == "GET" || method == "DELETE" || method == "HEAD" || method == "OPTIONS" {
-			req, err = http.NewRequest(method, url, nil)
-			reqSize = 0
-		} else {
-			bodyBytes := []byte(body)
-			req, err = http.NewRequest(method, url, bytes.NewBuffer(bodyBytes))
-			reqSize = len(bodyBytes)
+	// Tabs keep pointing at their requests when the workspace is renamed
+	workspaceRenamed = func(from, to string) {
+		for _, t := range tabs {
+			if t.Workspace == from {
+				t.Workspace = to
+			}
 		}
-		if err != nil {
-			jsonResponse.SetText(fmt.Sprintf("Request error: %v", err))
-			// statusLabel.SetText("")
-			headersBox.SetText("")
-			responseMeta.SetText("")
-			// Reset search state on error
-			originalText = ""
-			currentSearchQuery = ""
-			searchResults = []int{}
-			currentMatchIndex = -1
-			return
-		}
-		for k, v := range headers {
-			req.Header[k] = v
-		}
-		client := &http.Client{}
-		startTime := time.Now()
-		resp, err := client.Do(req)
-		elapsed := time.Since(startTime)
-		if err != nil {
-			jsonResponse.SetText(fmt.Sprintf("HTTP error: %v", err))
-			// statusLabel.SetText("")
-			headersBox.SetText("")
-			responseMeta.SetText("")
-			// Reset search state on error
-			originalText = ""
-			currentSearchQuery = ""
-			searchResults = []int{}
-			currentMatchIndex = -1
-			return
-		}
-		defer resp.Body.Close()
-		respBody, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			jsonResponse.SetText(fmt.Sprintf("Read error: %v", err))
-			// statusLabel.SetText("")
-			headersBox.SetText("")
-			responseMeta.SetText("")
-			// Reset search state on error
-			originalText = ""
-			currentSearchQuery = ""
-			searchResults = []int{}
-			currentMatchIndex = -1
-			return
-		}
-		// Try to pretty-print JSON
-		var prettyJSON bytes.Buffer
-		if json.Valid(respBody) {
-			err = json.Indent(&prettyJSON, respBody, "", "    ") // 4 spaces
-			if err == nil {
-				jsonResponse.SetText(prettyJSON.String())
+	}
+
+	// Tabs of a deleted workspace's requests become unsaved
+	workspaceRemoved = func(name string) {
+		for _, t := range tabs {
+			if t.Workspace == name {
+				t.Request = -1
+			}
+		}
+		refreshTabBar()
+	}
+
+	// Tabs of a deleted collection's requests become unsaved; later collections move up one
+	collectionRemoved = func(colIdx int) {
+		for _, t := range tabs {
+			if t.Workspace != workspaceSelect.Selected || t.Request < 0 || t.Collection < colIdx {
+				continue
+			}
+			if t.Collection == colIdx {
+				t.Request = -1
 			} else {
-				jsonResponse.SetText(string(respBody))
+				t.Collection--
 			}
-		} else {
-			jsonResponse.SetText(string(respBody))
-		}
-
-		// Reset search state when new response comes in
-		originalText = ""
-		currentSearchQuery = ""
-		searchResults = []int{}
-		currentMatchIndex = -1
-		// Status label (for headers panel)
-		// statusLabel.SetText(fmt.Sprintf("Status: %d %s", resp.StatusCode, resp.Status))
-		// Format response headers
-		headersStr := ""
-		for k, v := range resp.Header {
-			headersStr += fmt.Sprintf("%s: %s\n", k, strings.Join(v, ", "))
-		}
-		headersBox.SetText(headersStr)
-		// Set response meta info
-		respSize := len(respBody)
-		responseMeta.SetText(fmt.Sprintf("%d ms    Req: %s    Resp: %s",
-			elapsed.Milliseconds(),
-			formatSize(reqSize),
-			formatSize(respSize),
-		))
-		// Status code indicator with emoji and text (no color/style)
-		var statusText string
-		switch {
-		case resp.StatusCode >= 200 && resp.StatusCode < 300:
-			statusColor.FillColor = color.NRGBA{0, 200, 0, 255} // Green
-			statusText = fmt.Sprintf("✅ %d OK", resp.StatusCode)
-		case resp.StatusCode >= 400:
-			statusColor.FillColor = color.NRGBA{200, 0, 0, 255} // Red
-			statusText = fmt.Sprintf("🔴 %d Error", resp.StatusCode)
-		default:
-			statusColor.FillColor = color.NRGBA{200, 200, 0, 255} // Yellow
-			statusText = fmt.Sprintf("🟡 %d", resp.StatusCode)
 		}
-		statusColor.Refresh()
-		responseStatus.SetText(statusText)
-		responseStatus.Refresh()
+		refreshTabBar()
+	}
+
+	// Tabs of two requests that swapped places follow them, and so does the list selection
+	requestsSwapped = func(a, b int) {
+		for _, t := range tabs {
+			if t.Workspace != workspaceSelect.Selected || t.Collection != selectedCollectionIdx {
+				continue
+			}
+			switch t.Request {
+			case a:
+				t.Request = b
+			case b:
+				t.Request = a
+			}
+		}
+		linkSelection(tabs[activeTab])
 	}
 
 	// Add buttons for saving/loading requests and collections
-	saveReqBtn := widget.NewButton("Save Request", func() {
+	// Returns the workspace index of the selected collection, or -1 after telling the user what is missing
+	checkSaveTarget := func() int {
 		if workspaceSelect.Selected == "" || workspaceSelect.Selected == "+ New Workspace" {
 			dialog.ShowInformation("No Workspace", "Please select a workspace.", w)
-			return
+			return -1
 		}
 		if selectedCollectionIdx < 0 {
 			dialog.ShowInformation("No Collection", "Please select a collection.", w)
-			return
+			return -1
 		}
 		wsIdx := -1
 		for i, ws := range workspaces {
@@ -815,33 +4293,91 @@ func main() {
 		}
 		if wsIdx == -1 {
 			dialog.ShowError(fmt.Errorf("Workspace not found"), w)
-			return
+			return -1
 		}
 		if selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
 			dialog.ShowInformation("No Collection Selected", "Please select a collection.", w)
-			return
+			return -1
 		}
+		return wsIdx
+	}
+
+	saveToCollection := func(wsIdx int, req APIRequest) {
 		colIdx := selectedCollectionIdx
-		// Build request
-		headersMap := map[string]string{}
-		for k, v := range parseHeaders(headersEntry.Text) {
-			headersMap[k] = strings.Join(v, ", ")
-		}
-		req := APIRequest{
-			Name:    urlEntry.Text,
-			Method:  methodSelect.Selected,
-			URL:     urlEntry.Text,
-			Headers: headersMap,
-			Body:    bodyEntry.Text,
-		}
-		workspaces[wsIdx].Collections[colIdx].Requests = append(workspaces[wsIdx].Collections[colIdx].Requests, req)
-		err := saveWorkspaces(workspaces)
+		reqIdx := workspaces[wsIdx].Collections[colIdx].addRequest(nil, req)
+		err := saveModel()
 		if err != nil {
 			dialog.ShowError(err, w)
 			return
 		}
-		requestList.Refresh()
+		activity.add("Saved request %q to collection %q", req.Name, workspaces[wsIdx].Collections[colIdx].Name)
+		requestTree.Refresh()
+		markTabSaved(reqIdx, req)
 		dialog.ShowInformation("Saved", "Request saved to collection.", w)
+	}
+
+	// Ask for the name of a request being saved, suggesting one from the naming template
+	promptRequestName := func(req APIRequest, onName func(string)) {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(requestName(a.Preferences().String(prefNamingTemplate), req))
+		dialog.ShowForm("Save Request", "Save", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Request Name", nameEntry),
+		}, func(ok bool) {
+			if !ok || strings.TrimSpace(nameEntry.Text) == "" {
+				return
+			}
+			onName(strings.TrimSpace(nameEntry.Text))
+		}, w)
+	}
+
+	saveReqBtn := widget.NewButton("Save Request", func() {
+		wsIdx := checkSaveTarget()
+		if wsIdx == -1 {
+			return
+		}
+		req := formRequest()
+		promptRequestName(req, func(name string) {
+			req.Name = name
+			saveToCollection(wsIdx, req)
+		})
+	})
+
+	// Clone the form into a new unsaved request that differs only by method
+	duplicateMethodSelect := widget.NewSelect(methods, nil)
+	duplicateMethodSelect.PlaceHolder = "Method"
+	duplicateBtn := widget.NewButtonWithIcon("Duplicate as", theme.ContentCopyIcon(), func() {
+		method := duplicateMethodSelect.Selected
+		if method == "" {
+			dialog.ShowInformation("No Method", "Pick the method for the copy.", w)
+			return
+		}
+		// The copy opens in a tab of its own, so auto-save does not overwrite the original
+		copied := formRequest()
+		copied.Method = method
+		addTab(&requestTab{Collection: -1, Request: -1, Title: method + " copy"}, copied)
+		saveIndicator.SetText(fmt.Sprintf("Unsaved %s copy", method))
+	})
+
+	// Send, then save to the current collection under a prompted name if the response is 2xx
+	sendAndSaveBtn := widget.NewButtonWithIcon("Send & Save", theme.DocumentSaveIcon(), func() {
+		if checkSaveTarget() == -1 {
+			return
+		}
+		req := formRequest()
+		sendFromForm(func(statusCode int) {
+			if statusCode < 200 || statusCode >= 300 {
+				dialog.ShowInformation("Not Saved", fmt.Sprintf("The request returned %d, so it was not saved.", statusCode), w)
+				return
+			}
+			wsIdx := checkSaveTarget()
+			if wsIdx == -1 {
+				return
+			}
+			promptRequestName(req, func(name string) {
+				req.Name = name
+				saveToCollection(wsIdx, req)
+			})
+		})
 	})
 
 	loadReqBtn := widget.NewButton("Load Request", func() {
@@ -861,26 +4397,21 @@ func main() {
 			dialog.ShowError(fmt.Errorf("Invalid selection"), w)
 			return
 		}
-		coll := workspaces[wsIdx].Collections[colIdx]
-		if len(coll.Requests) == 0 {
+		requests := workspaces[wsIdx].Collections[colIdx].allRequests()
+		if len(requests) == 0 {
 			dialog.ShowInformation("No Requests", "No requests in this collection.", w)
 			return
 		}
 		// Show a dialog to pick a request
 		reqNames := []string{}
-		for _, r := range coll.Requests {
+		for _, r := range requests {
 			reqNames = append(reqNames, r.Name)
 		}
 		pick := widget.NewSelect(reqNames, func(sel string) {
-			for _, r := range coll.Requests {
+			for i, r := range requests {
 				if r.Name == sel {
-					methodSelect.SetSelected(r.Method)
-					urlEntry.SetText(r.URL)
-					headersEntry.SetText("")
-					for k, v := range r.Headers {
-						headersEntry.SetText(headersEntry.Text + k + ": " + v + "\n")
-					}
-					bodyEntry.SetText(r.Body)
+					openRequestTab(i, r)
+					break
 				}
 			}
 		})
@@ -888,6 +4419,95 @@ func main() {
 		d.Show()
 	})
 
+	// PATCH body builder (JSON Patch rows or JSON Merge Patch fields)
+	showPatchBuilder := func() {
+		rows := []*fyne.Container{}
+		rowsBox := container.NewVBox()
+		patchType := widget.NewSelect([]string{patchTypeJSONPatch, patchTypeMergePatch}, nil)
+		hint := widget.NewLabel("")
+
+		var addRow func()
+		addRow = func() {
+			op := widget.NewSelect(jsonPatchOps, nil)
+			op.SetSelected("replace")
+			if patchType.Selected == patchTypeMergePatch {
+				op.Hide()
+			}
+			path := widget.NewEntry()
+			path.SetPlaceHolder("/path/to/field")
+			value := widget.NewEntry()
+			value.SetPlaceHolder("value (JSON or text)")
+			var row *fyne.Container
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				for i, r := range rows {
+					if r == row {
+						rows = append(rows[:i], rows[i+1:]...)
+						break
+					}
+				}
+				rowsBox.Remove(row)
+			})
+			row = container.NewBorder(nil, nil, op, removeBtn, container.NewGridWithColumns(2, path, value))
+			rows = append(rows, row)
+			rowsBox.Add(row)
+		}
+
+		patchType.OnChanged = func(selected string) {
+			if selected == patchTypeMergePatch {
+				hint.SetText("Each row sets one field. Use a JSON null value to remove it.")
+			} else {
+				hint.SetText("For move/copy, put the 'from' path in the value column.")
+			}
+			for _, r := range rows {
+				if selected == patchTypeMergePatch {
+					r.Objects[1].Hide()
+				} else {
+					r.Objects[1].Show()
+				}
+				r.Refresh()
+			}
+		}
+		patchType.SetSelected(patchTypeJSONPatch)
+		addRow()
+
+		content := container.NewBorder(
+			container.NewVBox(patchType, hint),
+			widget.NewButtonWithIcon("Add Row", theme.ContentAddIcon(), addRow),
+			nil, nil,
+			container.NewVScroll(rowsBox),
+		)
+		d := dialog.NewCustomConfirm("Patch Builder", "Apply", "Cancel", content, func(ok bool) {
+			if !ok {
+				return
+			}
+			patchRows := []patchRow{}
+			for _, r := range rows {
+				fields := r.Objects[0].(*fyne.Container)
+				patchRows = append(patchRows, patchRow{
+					Op:    r.Objects[1].(*widget.Select).Selected,
+					Path:  fields.Objects[0].(*widget.Entry).Text,
+					Value: fields.Objects[1].(*widget.Entry).Text,
+				})
+			}
+			var data []byte
+			var err error
+			if patchType.Selected == patchTypeMergePatch {
+				data, err = buildMergePatch(patchRows)
+			} else {
+				data, err = buildJSONPatch(patchRows)
+			}
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			methodSelect.SetSelected("PATCH")
+			bodyEntry.SetText(string(data))
+			headersEntry.SetText(setHeaderLine(headersEntry.Text, "Content-Type", patchContentType(patchType.Selected)))
+		}, w)
+		d.Resize(fyne.NewSize(700, 450))
+		d.Show()
+	}
+
 	// Import/Export Dropdown Functions
 	importPostmanJSON := func() {
 		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
@@ -897,20 +4517,7 @@ func main() {
 			defer reader.Close()
 			var postman struct {
 				Info struct{ Name string } `json:"info"`
-				Item []struct {
-					Name    string `json:"name"`
-					Request struct {
-						Method string      `json:"method"`
-						URL    interface{} `json:"url"`
-						Header []struct {
-							Key   string `json:"key"`
-							Value string `json:"value"`
-						} `json:"header"`
-						Body struct {
-							Raw string `json:"raw"`
-						} `json:"body"`
-					} `json:"request"`
-				} `json:"item"`
+				Item []postmanItem         `json:"item"`
 			}
 			data, _ := ioutil.ReadAll(reader)
 			err = json.Unmarshal(data, &postman)
@@ -924,31 +4531,12 @@ func main() {
 			}
 			for i, ws := range workspaces {
 				if ws.Name == workspaceSelect.Selected {
+					// Folders of the Postman collection become folders of the new collection
 					col := Collection{Name: postman.Info.Name}
-					for _, item := range postman.Item {
-						headers := map[string]string{}
-						for _, h := range item.Request.Header {
-							headers[h.Key] = h.Value
-						}
-						urlStr := ""
-						switch v := item.Request.URL.(type) {
-						case string:
-							urlStr = v
-						case map[string]interface{}:
-							if raw, ok := v["raw"].(string); ok {
-								urlStr = raw
-							}
-						}
-						col.Requests = append(col.Requests, APIRequest{
-							Name:    item.Name,
-							Method:  item.Request.Method,
-							URL:     urlStr,
-							Headers: headers,
-							Body:    item.Request.Body.Raw,
-						})
-					}
+					col.Requests, col.Folders = fromPostmanItems(postman.Item)
 					workspaces[i].Collections = append(workspaces[i].Collections, col)
-					_ = saveWorkspaces(workspaces)
+					_ = saveModel()
+					activity.add("Imported Postman collection %q (%d requests)", col.Name, col.requestCount())
 					// Update collection dropdown options
 					collectionOptions := []string{"+ New Collection"}
 					for _, col := range workspaces[i].Collections {
@@ -957,10 +4545,253 @@ func main() {
 					collectionSelect.Options = collectionOptions
 					collectionSelect.SetSelected(col.Name)
 					selectedCollectionIdx = len(workspaces[i].Collections) - 1
-					requestList.Refresh()
+					requestTree.Refresh()
+				}
+			}
+		}, w)
+	}
+
+	// Create a collection from an OpenAPI 3 or Swagger 2 spec (JSON or YAML)
+	importOpenAPISpec := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			data, err := ioutil.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Read error: %v", err), w)
+				return
+			}
+			col, err := parseOpenAPI(data)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Invalid OpenAPI spec %s: %v", reader.URI().Name(), err), w)
+				return
+			}
+			workspaces[wsIdx].Collections = append(workspaces[wsIdx].Collections, col)
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			activity.add("Imported OpenAPI spec %s as collection %q (%d requests)", reader.URI().Name(), col.Name, col.requestCount())
+			collectionOptions := []string{"+ New Collection"}
+			for _, c := range workspaces[wsIdx].Collections {
+				collectionOptions = append(collectionOptions, c.Name)
+			}
+			collectionSelect.Options = collectionOptions
+			collectionSelect.SetSelected(col.Name)
+			selectedCollectionIdx = len(workspaces[wsIdx].Collections) - 1
+			requestTree.Refresh()
+			if _, ok := col.Variables[openAPIBaseURLVar]; ok {
+				dialog.ShowInformation("OpenAPI Imported", "The spec names no absolute server URL, so the requests start with {{baseUrl}}.\nSet it in the collection's Variables.", w)
+			}
+		}, w)
+	}
+
+	// Fill the form from a devtools "Copy as fetch" snippet; the result is unsaved
+	importFetchSnippet := func() {
+		snippetEntry := widget.NewMultiLineEntry()
+		snippetEntry.SetPlaceHolder("fetch(\"https://example.com/api\", {\n  \"method\": \"POST\",\n  ...\n});")
+		snippetEntry.Wrapping = fyne.TextWrapBreak
+		snippetEntry.SetMinRowsVisible(12)
+		d := dialog.NewCustomConfirm("Import fetch() Call", "Import", "Cancel", snippetEntry, func(ok bool) {
+			if !ok {
+				return
+			}
+			req, err := parseFetchSnippet(snippetEntry.Text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Could not parse fetch call: %v", err), w)
+				return
+			}
+			addTab(&requestTab{Collection: -1, Request: -1, Title: "Imported " + req.Method}, req)
+			saveIndicator.SetText("Imported request (unsaved)")
+			updateBodyWarning()
+			activity.add("Imported fetch() call %s %s", req.Method, req.URL)
+		}, w)
+		d.Resize(fyne.NewSize(700, 450))
+		d.Show()
+	}
+
+	// Fill the form from a curl command, e.g. a snippet from API docs; the result is unsaved
+	importCurlCommand := func() {
+		curlEntry := widget.NewMultiLineEntry()
+		curlEntry.SetPlaceHolder("curl -X POST 'https://example.com/api/users' \\\n  -H 'Content-Type: application/json' \\\n  -d '{\"name\": \"test\"}'")
+		curlEntry.Wrapping = fyne.TextWrapBreak
+		curlEntry.SetMinRowsVisible(12)
+		d := dialog.NewCustomConfirm("Paste cURL", "Import", "Cancel", curlEntry, func(ok bool) {
+			if !ok {
+				return
+			}
+			req, err := parseCurlCommand(curlEntry.Text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Could not parse curl command: %v", err), w)
+				return
+			}
+			addTab(&requestTab{Collection: -1, Request: -1, Title: "Imported " + req.Method}, req)
+			saveIndicator.SetText("Imported request (unsaved)")
+			updateBodyWarning()
+			activity.add("Imported curl command %s %s", req.Method, req.URL)
+		}, w)
+		d.Resize(fyne.NewSize(700, 450))
+		d.Show()
+	}
+
+	// Import a raw HTTP request (request line, headers, blank line, body)
+	importRawHTTP := func() {
+		rawEntry := widget.NewMultiLineEntry()
+		rawEntry.SetPlaceHolder("POST /api/users HTTP/1.1\nHost: example.com\nContent-Type: application/json\n\n{\"name\": \"test\"}")
+		rawEntry.Wrapping = fyne.TextWrapBreak
+		rawEntry.SetMinRowsVisible(12)
+		d := dialog.NewCustomConfirm("Import Raw HTTP Request", "Import", "Cancel", rawEntry, func(ok bool) {
+			if !ok {
+				return
+			}
+			req, err := parseRawHTTPRequest(rawEntry.Text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Could not parse HTTP request: %v", err), w)
+				return
+			}
+			addTab(&requestTab{Collection: -1, Request: -1, Title: "Imported " + req.Method}, req)
+			saveIndicator.SetText("Imported request (unsaved)")
+			updateBodyWarning()
+			activity.add("Imported raw HTTP request %s %s", req.Method, req.URL)
+		}, w)
+		d.Resize(fyne.NewSize(700, 450))
+		d.Show()
+	}
+
+	// Read a .env file into the environment named after it, adding to the variables
+	// of an existing environment with that name
+	importDotEnv := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 {
+			dialog.ShowInformation("No Workspace", "Select a workspace first.", w)
+			return
+		}
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			data, err := ioutil.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Read error: %v", err), w)
+				return
+			}
+			vars, problems := parseDotEnv(string(data))
+			if len(vars) == 0 {
+				dialog.ShowError(fmt.Errorf("No variables found in %s\n%s", reader.URI().Name(), strings.Join(problems, "\n")), w)
+				return
+			}
+			name := dotEnvEnvironmentName(reader.URI().Name())
+			variablesMu.Lock()
+			envs := workspaces[wsIdx].Environments
+			merged := false
+			for i := range envs {
+				if envs[i].Name == name {
+					if envs[i].Variables == nil {
+						envs[i].Variables = map[string]string{}
+					}
+					for k, v := range vars {
+						envs[i].Variables[k] = v
+					}
+					merged = true
+				}
+			}
+			if !merged {
+				workspaces[wsIdx].Environments = append(envs, Environment{Name: name, Variables: vars})
+			}
+			variablesMu.Unlock()
+			if err := saveModel(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			refreshEnvironmentOptions()
+			envSelect.SetSelected(name)
+			activity.add("Imported %d variables from %s into environment %q", len(vars), reader.URI().Name(), name)
+			message := fmt.Sprintf("Imported %d variables into environment \"%s\".", len(vars), name)
+			if len(problems) > 0 {
+				message += "\n\nSkipped:\n" + strings.Join(problems, "\n")
+			}
+			dialog.ShowInformation(".env Imported", message, w)
+		}, w)
+	}
+
+	// Save the named environment of the current workspace in Postman environment format
+	exportEnvironmentJSON := func(name string) {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || name == noEnvironment {
+			dialog.ShowInformation("No Environment", "Select an environment first.", w)
+			return
+		}
+		for _, env := range workspaces[wsIdx].Environments {
+			if env.Name != name {
+				continue
+			}
+			data, _ := json.MarshalIndent(toPostmanEnvironment(env), "", "  ")
+			save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil || writer == nil {
+					return
+				}
+				defer writer.Close()
+				if _, err := writer.Write(data); err != nil {
+					dialog.ShowError(fmt.Errorf("Write error: %v", err), w)
+					return
 				}
+				activity.add("Exported environment %q to %s", env.Name, writer.URI().Name())
+			}, w)
+			save.SetFileName(env.Name + ".postman_environment.json")
+			save.Show()
+			return
+		}
+	}
+
+	// Copy the form's request as a share link a teammate can paste into Import
+	exportShareLink := func() {
+		link, err := encodeShareLink(formRequest())
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		w.Clipboard().SetContent(link)
+		linkEntry := widget.NewMultiLineEntry()
+		linkEntry.Wrapping = fyne.TextWrapBreak
+		linkEntry.SetText(link)
+		linkEntry.SetMinRowsVisible(6)
+		content := container.NewBorder(widget.NewLabel("Auth credentials, the Authorization, Proxy-Authorization and Cookie headers and proxy passwords are left out of the link.\nOther secret headers, such as X-API-Key, are not."), nil, nil, nil, linkEntry)
+		d := dialog.NewCustom("Share Link (copied to clipboard)", "Close", content, w)
+		d.Resize(fyne.NewSize(700, 300))
+		d.Show()
+		activity.add("Copied share link for %s %s", methodSelect.Selected, fullURL())
+	}
+
+	// Fill the form from a pasted share link; the result is unsaved
+	importShareLink := func() {
+		linkEntry := widget.NewMultiLineEntry()
+		linkEntry.Wrapping = fyne.TextWrapBreak
+		linkEntry.SetPlaceHolder(shareLinkScheme + "://request?v=1&data=...")
+		linkEntry.SetMinRowsVisible(6)
+		d := dialog.NewCustomConfirm("Import Share Link", "Import", "Cancel", linkEntry, func(ok bool) {
+			if !ok {
+				return
+			}
+			req, err := decodeShareLink(linkEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
 			}
+			addTab(&requestTab{Collection: -1, Request: -1, Title: "Imported " + req.Method}, req)
+			saveIndicator.SetText("Imported request (unsaved)")
+			updateBodyWarning()
+			activity.add("Imported share link %s %s", req.Method, req.URL)
 		}, w)
+		d.Resize(fyne.NewSize(700, 300))
+		d.Show()
 	}
 
 	exportCollectionJSON := func() {
@@ -987,25 +4818,7 @@ func main() {
 				"name":   coll.Name,
 				"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
 			},
-			"item": []interface{}{},
-		}
-		for _, r := range coll.Requests {
-			item := map[string]interface{}{
-				"name": r.Name,
-				"request": map[string]interface{}{
-					"method": r.Method,
-					"header": func() []interface{} {
-						h := []interface{}{}
-						for k, v := range r.Headers {
-							h = append(h, map[string]interface{}{"key": k, "value": v})
-						}
-						return h
-					}(),
-					"url":  r.URL,
-					"body": map[string]interface{}{"mode": "raw", "raw": r.Body},
-				},
-			}
-			postman["item"] = append(postman["item"].([]interface{}), item)
+			"item": toPostmanItems(coll.Requests, coll.Folders),
 		}
 		data, _ := json.MarshalIndent(postman, "", "  ")
 		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
@@ -1016,17 +4829,73 @@ func main() {
 			_, err = writer.Write(data)
 			if err != nil {
 				dialog.ShowError(fmt.Errorf("Write error: %v", err), w)
+				return
+			}
+			activity.add("Exported collection %q to %s", coll.Name, writer.URI().Name())
+			// Offer the active environment too, so the collection's variables travel with it
+			if env := envSelect.Selected; env != noEnvironment {
+				dialog.ShowConfirm("Export Environment",
+					fmt.Sprintf("Also export the active environment \"%s\"?", env),
+					func(ok bool) {
+						if ok {
+							exportEnvironmentJSON(env)
+						}
+					}, w)
+			}
+		}, w)
+	}
+
+	// Save the selected collection as an OpenAPI 3.0 skeleton
+	exportCollectionOpenAPI := func() {
+		wsIdx := currentWorkspaceIdx()
+		if wsIdx == -1 || selectedCollectionIdx < 0 || selectedCollectionIdx >= len(workspaces[wsIdx].Collections) {
+			dialog.ShowInformation("Select", "Select a workspace and collection.", w)
+			return
+		}
+		coll := &workspaces[wsIdx].Collections[selectedCollectionIdx]
+		data, skipped, err := exportOpenAPI(coll)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write(data); err != nil {
+				dialog.ShowError(fmt.Errorf("Write error: %v", err), w)
+				return
+			}
+			activity.add("Exported collection %q as OpenAPI to %s", coll.Name, writer.URI().Name())
+			if len(skipped) > 0 {
+				dialog.ShowInformation("OpenAPI Exported",
+					fmt.Sprintf("These requests repeat the path and method of an earlier one and were left out:\n\n%s", strings.Join(skipped, "\n")), w)
 			}
 		}, w)
+		save.SetFileName(sanitizeFileName(coll.Name + ".openapi.json"))
+		save.Show()
 	}
 
 	// Import Dropdown
-	importOptions := []string{"Postman Collection JSON"}
+	importOptions := []string{"Postman Collection JSON", "OpenAPI Spec", "Paste cURL", "fetch() Call", "Raw HTTP Request", "Share Link", ".env File"}
 	var importSelect *widget.Select
 	importSelect = widget.NewSelect(importOptions, func(selected string) {
 		switch selected {
 		case "Postman Collection JSON":
 			importPostmanJSON()
+		case "OpenAPI Spec":
+			importOpenAPISpec()
+		case "Paste cURL":
+			importCurlCommand()
+		case "fetch() Call":
+			importFetchSnippet()
+		case "Raw HTTP Request":
+			importRawHTTP()
+		case "Share Link":
+			importShareLink()
+		case ".env File":
+			importDotEnv()
 		}
 		// Reset selection after action
 		go func() {
@@ -1037,12 +4906,52 @@ func main() {
 	importSelect.PlaceHolder = "Import..."
 
 	// Export Dropdown
-	exportOptions := []string{"Collection as JSON"}
+	// Export the last request and its response as one file for attaching to tickets
+	exportInteraction := func(asMarkdown bool) {
+		if lastInteraction == nil {
+			dialog.ShowInformation("No Interaction", "Send a request first.", w)
+			return
+		}
+		var data []byte
+		fileName := "interaction.json"
+		if asMarkdown {
+			data = []byte(lastInteraction.markdown())
+			fileName = "interaction.md"
+		} else {
+			data, _ = json.MarshalIndent(lastInteraction, "", "  ")
+		}
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			_, err = writer.Write(data)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("Write error: %v", err), w)
+				return
+			}
+			activity.add("Exported interaction to %s", writer.URI().Name())
+		}, w)
+		save.SetFileName(fileName)
+		save.Show()
+	}
+
+	exportOptions := []string{"Collection as JSON", "Collection as OpenAPI", "Active Environment as JSON", "Request as Share Link", "Interaction as JSON", "Interaction as Markdown"}
 	var exportSelect *widget.Select
 	exportSelect = widget.NewSelect(exportOptions, func(selected string) {
 		switch selected {
 		case "Collection as JSON":
 			exportCollectionJSON()
+		case "Collection as OpenAPI":
+			exportCollectionOpenAPI()
+		case "Active Environment as JSON":
+			exportEnvironmentJSON(envSelect.Selected)
+		case "Request as Share Link":
+			exportShareLink()
+		case "Interaction as JSON":
+			exportInteraction(false)
+		case "Interaction as Markdown":
+			exportInteraction(true)
 		}
 		// Reset selection after action
 		go func() {
@@ -1059,28 +4968,46 @@ func main() {
 		container.NewHBox(
 			container.NewVBox(
 				widget.NewLabelWithStyle("Workspaces", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-				workspaceSelect,
+				container.NewBorder(nil, nil, nil, workspaceMenuBtn, workspaceSelect),
 			),
 			layout.NewSpacer(),
 			container.NewVBox(
 				importSelect,
 				exportSelect,
+				settingsBtn,
 			),
 		),
 		widget.NewSeparator(),
 		// Collections section with dropdown
 		widget.NewLabelWithStyle("Collections", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-		collectionSelect,
+		container.NewBorder(nil, nil, nil, container.NewHBox(collectionVarsBtn, collectionMenuBtn), collectionSelect),
+		collectionEnvLabel,
+		widget.NewSeparator(),
+		// Environment section with dropdown and manage button
+		widget.NewLabelWithStyle("Environment", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, container.NewHBox(bindEnvBtn, manageEnvBtn), envSelect),
+		container.NewGridWithColumns(2,
+			widget.NewButtonWithIcon("Scratchpad", theme.DocumentCreateIcon(), showScratchpad),
+			widget.NewButtonWithIcon("Cookies", theme.StorageIcon(), showCookies),
+		),
 		widget.NewSeparator(),
 		// Requests section with scrollable list (limited to 10 items visible)
-		widget.NewLabelWithStyle("Requests", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, container.NewHBox(
+			widget.NewButton("Replace...", showFindReplace),
+			widget.NewButtonWithIcon("", theme.FolderNewIcon(), func() { newFolder(nil) }),
+			widget.NewButton("Compare...", showCompareRequests),
+			widget.NewButtonWithIcon("Run Collection", theme.MediaPlayIcon(), showCollectionRunner),
+		),
+			widget.NewLabelWithStyle("Requests", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})),
 		func() *container.Scroll {
-			scroll := container.NewVScroll(requestList)
+			scroll := container.NewVScroll(requestTree)
 			scroll.SetMinSize(fyne.NewSize(250, 300)) // Limit height to show ~10 items
 			return scroll
 		}(),
 		widget.NewSeparator(),
-		flowsLabel,
+		widget.NewLabelWithStyle("Flows", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		flowSelect,
+		container.NewHBox(newFlowBtn, editFlowBtn, deleteFlowBtn, layout.NewSpacer(), runFlowBtn),
 	)
 
 	// Request Row: method, URL, Send button (URL entry with larger width and resizable)
@@ -1093,19 +5020,324 @@ func main() {
 	sendBtn.Importance = widget.HighImportance
 	sendBtn.Resize(fyne.NewSize(400, 44)) // Wider and taller
 
-	requestRow := container.NewBorder(nil, nil, nil, sendBtn, urlSplit)
+	requestRow := container.NewBorder(nil, nil, nil, container.NewHBox(queueLabel, validateBtn, sendBtn), urlSplit)
 
 	// Save/Load Row
 	saveLoadRow := container.NewHBox(
+		saveIndicator,
 		layout.NewSpacer(),
+		duplicateBtn,
+		duplicateMethodSelect,
+		probeBtn,
+		sendAndSaveBtn,
 		saveReqBtn,
 		loadReqBtn,
 	)
 
 	// Headers/Body Tabs
-	headersTab := container.NewTabItem("Headers", headersEntry)
-	bodyTab := container.NewTabItem("Body", bodyEntry)
-	requestTabs := container.NewAppTabs(headersTab, bodyTab)
+	headersTab := container.NewTabItem("Headers", container.NewBorder(nil, headerSuggestionsRow, nil, nil, headersEntry))
+	patchBtn := widget.NewButton("Patch Builder...", showPatchBuilder)
+	// Replace the body with the clipboard; JSON payloads also get a JSON Content-Type
+	pasteBodyBtn := widget.NewButtonWithIcon("Paste as Body", theme.ContentPasteIcon(), func() {
+		content := w.Clipboard().Content()
+		if strings.TrimSpace(content) == "" {
+			dialog.ShowInformation("Clipboard Empty", "There is no text on the clipboard.", w)
+			return
+		}
+		bodyEntry.SetText(content)
+		contentType := parseHeaders(headersEntry.Text).Get("Content-Type")
+		if json.Valid([]byte(content)) && !strings.Contains(strings.ToLower(contentType), "json") {
+			headersEntry.SetText(setHeaderLine(headersEntry.Text, "Content-Type", "application/json"))
+		}
+	})
+	browseBodyFileBtn := widget.NewButtonWithIcon("Browse...", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			bodyFileEntry.SetText(reader.URI().Path())
+		}, w)
+	})
+	// Per-line problems for NDJSON bodies; variables are assumed to resolve to valid JSON here
+	ndjsonProblems := widget.NewLabel("")
+	ndjsonProblems.Wrapping = fyne.TextWrapWord
+	ndjsonProblems.Hide()
+	updateNDJSONProblems := func() {
+		if bodyModeFromLabel(bodyModeSelect.Selected) != bodyModeNDJSON {
+			ndjsonProblems.Hide()
+			return
+		}
+		problems := ndjsonErrors(variablePattern.ReplaceAllString(bodyEntry.Text, "0"))
+		if len(problems) == 0 {
+			ndjsonProblems.SetText("✅ Every line is valid JSON")
+		} else {
+			ndjsonProblems.SetText("❌ " + strings.Join(problems, "\n❌ "))
+		}
+		ndjsonProblems.Show()
+	}
+	onBodyChanged := bodyEntry.OnChanged
+	bodyEntry.OnChanged = func(s string) {
+		onBodyChanged(s)
+		updateNDJSONProblems()
+	}
+	bodyFilePanel := container.NewVBox(
+		container.NewBorder(nil, nil, nil, browseBodyFileBtn, bodyFileEntry),
+		chunkedCheck,
+	)
+	// Form fields as rows of key, value and a File toggle; file values are paths picked with Browse
+	formFieldRows := container.NewVBox()
+	rebuildFormFieldRows = func() {
+		formFieldRows.RemoveAll()
+		isMultipart := bodyModeFromLabel(bodyModeSelect.Selected) == bodyModeMultipart
+		for i, f := range formFields {
+			i := i
+			keyEntry := widget.NewEntry()
+			keyEntry.SetPlaceHolder("Key")
+			keyEntry.SetText(f.Key)
+			valueEntry := widget.NewEntry()
+			valueEntry.SetPlaceHolder("Value")
+			if f.IsFile && isMultipart {
+				valueEntry.SetPlaceHolder("Path of the file to upload")
+			}
+			valueEntry.SetText(f.Value)
+			keyEntry.OnChanged = func(s string) {
+				formFields[i].Key = s
+				formChanged()
+			}
+			valueEntry.OnChanged = func(s string) {
+				formFields[i].Value = s
+				formChanged()
+			}
+			// Only multipart bodies can carry files
+			fileCheck := widget.NewCheck("File", nil)
+			fileCheck.SetChecked(f.IsFile)
+			if !isMultipart {
+				fileCheck.Hide()
+			}
+			fileCheck.OnChanged = func(checked bool) {
+				formFields[i].IsFile = checked
+				rebuildFormFieldRows()
+				formChanged()
+			}
+			// Each part of a multipart body may have its own type, e.g. a JSON metadata part
+			typeEntry := widget.NewEntry()
+			typeEntry.SetPlaceHolder("Content-Type (optional)")
+			typeEntry.SetText(f.ContentType)
+			typeEntry.OnChanged = func(s string) {
+				formFields[i].ContentType = strings.TrimSpace(s)
+				formChanged()
+			}
+			browseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+				dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+					if err != nil || reader == nil {
+						return
+					}
+					reader.Close()
+					valueEntry.SetText(reader.URI().Path())
+				}, w)
+			})
+			if !f.IsFile || !isMultipart {
+				browseBtn.Hide()
+			}
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				formFields = append(formFields[:i], formFields[i+1:]...)
+				rebuildFormFieldRows()
+				formChanged()
+			})
+			entries := container.NewGridWithColumns(2, keyEntry, valueEntry)
+			if isMultipart {
+				entries = container.NewGridWithColumns(3, keyEntry, valueEntry, typeEntry)
+			}
+			formFieldRows.Add(container.NewBorder(nil, nil, nil, container.NewHBox(fileCheck, browseBtn, removeBtn), entries))
+		}
+	}
+	addFormFieldBtn := widget.NewButtonWithIcon("Add Field", theme.ContentAddIcon(), func() {
+		formFields = append(formFields, FormField{})
+		rebuildFormFieldRows()
+	})
+	rebuildFormFieldRows()
+	formFieldsPanel := container.NewBorder(container.NewHBox(addFormFieldBtn), nil, nil, nil,
+		container.NewVScroll(formFieldRows))
+	// Show the editor for the selected body mode
+	updateBodyMode := func() {
+		mode := bodyModeFromLabel(bodyModeSelect.Selected)
+		bodyEntry.Hide()
+		bodyFilePanel.Hide()
+		formFieldsPanel.Hide()
+		pasteBodyBtn.Hide()
+		patchBtn.Hide()
+		switch mode {
+		case bodyModeFile:
+			bodyFilePanel.Show()
+		case bodyModeMultipart, bodyModeURLEncoded:
+			rebuildFormFieldRows()
+			formFieldsPanel.Show()
+		default:
+			bodyEntry.Show()
+			pasteBodyBtn.Show()
+			patchBtn.Show()
+		}
+		updateNDJSONProblems()
+	}
+	bodyModeSelect.OnChanged = func(string) {
+		updateBodyMode()
+		formChanged()
+	}
+	updateBodyMode()
+	bodyTab := container.NewTabItem("Body", container.NewBorder(
+		container.NewHBox(bodyModeSelect, layout.NewSpacer(), pasteBodyBtn, patchBtn), ndjsonProblems, nil, nil,
+		container.NewStack(bodyEntry, bodyFilePanel, formFieldsPanel),
+	))
+	// Query parameters as an editable table, kept in sync with the URL both ways
+	paramsRows := container.NewVBox()
+	var paramKeys, paramValues []*widget.Entry
+	updatingURLFromParams := false
+	parsedURL := a.Preferences().Bool(prefParsedURL)
+	var rebuildParamRows func(params []queryParam)
+	currentParams := func() []queryParam {
+		params := []queryParam{}
+		for i := range paramKeys {
+			params = append(params, queryParam{Key: paramKeys[i].Text, Value: paramValues[i].Text})
+		}
+		return params
+	}
+	paramsChanged := func() {
+		if parsedURL {
+			// The URL box doesn't show the query, so only the form changes
+			formChanged()
+			return
+		}
+		updatingURLFromParams = true
+		urlEntry.SetText(withQueryParams(urlEntry.Text, currentParams()))
+		updatingURLFromParams = false
+	}
+	fullURL = func() string {
+		if parsedURL {
+			return withQueryParams(urlEntry.Text, currentParams())
+		}
+		return urlEntry.Text
+	}
+	showURL = func(rawURL string) {
+		if !parsedURL {
+			urlEntry.SetText(rawURL)
+			return
+		}
+		base, _, fragment := splitURL(rawURL)
+		updatingURLFromParams = true
+		urlEntry.SetText(base + fragment)
+		updatingURLFromParams = false
+		rebuildParamRows(parseQueryParams(rawURL))
+	}
+	rebuildParamRows = func(params []queryParam) {
+		paramsRows.RemoveAll()
+		paramKeys, paramValues = nil, nil
+		for i, p := range params {
+			i := i
+			keyEntry := widget.NewEntry()
+			keyEntry.SetPlaceHolder("Key")
+			keyEntry.SetText(p.Key)
+			valueEntry := widget.NewEntry()
+			valueEntry.SetPlaceHolder("Value")
+			valueEntry.SetText(p.Value)
+			keyEntry.OnChanged = func(string) { paramsChanged() }
+			valueEntry.OnChanged = func(string) { paramsChanged() }
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				remaining := []queryParam{}
+				for j := range paramKeys {
+					if j != i {
+						remaining = append(remaining, queryParam{Key: paramKeys[j].Text, Value: paramValues[j].Text})
+					}
+				}
+				rebuildParamRows(remaining)
+				paramsChanged()
+			})
+			paramKeys = append(paramKeys, keyEntry)
+			paramValues = append(paramValues, valueEntry)
+			paramsRows.Add(container.NewBorder(nil, nil, nil, removeBtn, container.NewGridWithColumns(2, keyEntry, valueEntry)))
+		}
+	}
+	addParamBtn := widget.NewButtonWithIcon("Add Parameter", theme.ContentAddIcon(), func() {
+		rebuildParamRows(append(currentParams(), queryParam{}))
+	})
+	onURLChanged := urlEntry.OnChanged
+	urlEntry.OnChanged = func(s string) {
+		onURLChanged(s)
+		if updatingURLFromParams {
+			return
+		}
+		if !parsedURL {
+			rebuildParamRows(parseQueryParams(s))
+			return
+		}
+		// A query typed or pasted into the URL box moves to the table
+		if base, query, fragment := splitURL(s); query != "" {
+			rebuildParamRows(append(currentParams(), parseQueryParams(s)...))
+			updatingURLFromParams = true
+			urlEntry.SetText(base + fragment)
+			updatingURLFromParams = false
+		}
+	}
+	rebuildParamRows(parseQueryParams(urlEntry.Text))
+	parsedURLCheck := widget.NewCheck("Parsed URL (the URL box leaves the query to this table)", func(checked bool) {
+		if checked == parsedURL {
+			return
+		}
+		current := fullURL()
+		parsedURL = checked
+		a.Preferences().SetBool(prefParsedURL, checked)
+		showURL(current)
+	})
+	parsedURLCheck.SetChecked(parsedURL)
+	paramsTab := container.NewTabItem("Params", container.NewBorder(
+		container.NewHBox(addParamBtn, layout.NewSpacer(), parsedURLCheck), nil, nil, nil,
+		container.NewVScroll(paramsRows),
+	))
+
+	// Auth fields for the selected type; a header typed in the Headers tab still wins
+	authHint := widget.NewLabelWithStyle("An Authorization header typed in the Headers tab takes precedence.",
+		fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	bearerForm := widget.NewForm(widget.NewFormItem("Token", authTokenEntry))
+	basicForm := widget.NewForm(
+		widget.NewFormItem("Username", authUsernameEntry),
+		widget.NewFormItem("Password", authPasswordEntry),
+	)
+	updateAuthType := func() {
+		bearerForm.Hide()
+		basicForm.Hide()
+		switch authTypeFromLabel(authTypeSelect.Selected) {
+		case authBearer:
+			bearerForm.Show()
+		case authBasic:
+			basicForm.Show()
+		}
+	}
+	authTypeSelect.OnChanged = func(string) {
+		updateAuthType()
+		formChanged()
+	}
+	updateAuthType()
+	authTab := container.NewTabItem("Auth", container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Type", authTypeSelect)),
+		bearerForm,
+		basicForm,
+		authHint,
+	))
+	// Per-request settings, grouped so they stay discoverable as they grow
+	settingsTab := container.NewTabItem("Settings", widget.NewForm(
+		widget.NewFormItem("Host", hostEntry),
+		widget.NewFormItem("Environment", envOverrideSelect),
+		widget.NewFormItem("Timeout", timeoutEntry),
+		widget.NewFormItem("Retries", retriesEntry),
+		widget.NewFormItem("Expected status", expectedStatusEntry),
+		widget.NewFormItem("Redirects", followRedirectsCheck),
+		widget.NewFormItem("TLS", insecureCheck),
+		widget.NewFormItem("Proxy", proxyEntry),
+		widget.NewFormItem("Language", languageEntry),
+		widget.NewFormItem("Compression", gzipCheck),
+		widget.NewFormItem("Caching", conditionalCheck),
+	))
+	requestTabs := container.NewAppTabs(paramsTab, headersTab, bodyTab, authTab, settingsTab)
 	requestTabs.SetTabLocation(container.TabLocationTop)
 
 	// JSONata input row: make entry and button resizable
@@ -1114,6 +5346,7 @@ func main() {
 	jsonataTab := container.NewTabItem("JSONata", container.NewVBox(
 		widget.NewLabelWithStyle("JSONata Query", fyne.TextAlignLeading, fyne.TextStyle{}),
 		container.NewHSplit(jsonataEntry, jsonataBtn),
+		jsonataAutoCheck,
 		widget.NewLabelWithStyle("Output", fyne.TextAlignLeading, fyne.TextStyle{}),
 		jsonataOutput,
 	))
@@ -1121,13 +5354,48 @@ func main() {
 	// Response tabs with status container
 	jsonTabContent := container.NewVBox(
 		responseStatusContainer,
+		container.NewHBox(prettyCheck, wrapCheck, layout.NewSpacer(), copyMinifiedBtn, goStructBtn, saveResponseBtn),
+		truncatedBanner,
+		downloadProgress,
+		pinnedSearchRow,
 		jsonResponseWithOverlay,
 	)
-	responseTabs := container.NewAppTabs(
+	responseTabs = container.NewAppTabs(
 		container.NewTabItem("JSON", jsonTabContent),
-		container.NewTabItem("Preview", widget.NewLabel("Preview will appear here.")),
+		container.NewTabItem("Preview", container.NewGridWrap(fyne.NewSize(1000, 600),
+			container.NewStack(previewImage, previewRichScroll, container.NewVBox(previewMessage)))),
 		container.NewTabItem("Visualize", widget.NewLabel("Visualization will appear here.")),
+		// The table scrolls itself, so give it the same fixed area as the JSON view
+		container.NewTabItem("Table", container.NewGridWrap(fyne.NewSize(1000, 600),
+			container.NewStack(tableView, tableUnavailable))),
 		jsonataTab,
+		container.NewTabItem("Transform", container.NewVBox(
+			widget.NewLabelWithStyle("Mapping (target = JSONPath)", fyne.TextAlignLeading, fyne.TextStyle{}),
+			transformEntry,
+			widget.NewLabelWithStyle("Result", fyne.TextAlignLeading, fyne.TextStyle{}),
+			transformOutput,
+			transformProblems,
+		)),
+		container.NewTabItem("Raw Headers", container.NewBorder(captureRawHeadersCheck, nil, nil, nil, rawHeadersBox)),
+		container.NewTabItem("Extract", container.NewVBox(
+			widget.NewLabelWithStyle("Rules (variable = $.path or variable = header:Name)", fyne.TextAlignLeading, fyne.TextStyle{}),
+			extractEntry,
+			widget.NewLabelWithStyle("Extracted", fyne.TextAlignLeading, fyne.TextStyle{}),
+			extractResults,
+		)),
+		container.NewTabItem("Tests", container.NewVBox(
+			container.NewHBox(
+				widget.NewLabelWithStyle("Assertions", fyne.TextAlignLeading, fyne.TextStyle{}),
+				layout.NewSpacer(),
+				widget.NewButtonWithIcon("Syntax", theme.HelpIcon(), func() {
+					help := widget.NewLabelWithStyle(assertionHelp, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+					dialog.ShowCustom("Assertion Syntax", "Close", help, w)
+				}),
+			),
+			testsEntry,
+			widget.NewLabelWithStyle("Results", fyne.TextAlignLeading, fyne.TextStyle{}),
+			testsResults,
+		)),
 	)
 	responseTabs.SetTabLocation(container.TabLocationTop)
 
@@ -1139,22 +5407,88 @@ func main() {
 		responseTabs,
 	)
 
+	// Activity console: collapsible, with clear and copy
+	consoleText := widget.NewLabel("")
+	consoleText.Wrapping = fyne.TextWrapWord
+	consoleScroll := container.NewVScroll(consoleText)
+	consoleScroll.SetMinSize(fyne.NewSize(0, 160))
+	activity.onChange = func(text string) {
+		consoleText.SetText(text)
+		consoleScroll.ScrollToBottom()
+	}
+	consoleText.SetText(activity.text())
+	clearConsoleBtn := widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), activity.clear)
+	copyConsoleBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(activity.text())
+	})
+	console := widget.NewAccordion(widget.NewAccordionItem("Console", container.NewBorder(
+		container.NewHBox(layout.NewSpacer(), clearConsoleBtn, copyConsoleBtn), nil, nil, nil,
+		consoleScroll,
+	)))
+
 	// Main right pane: vertical, with clear separation
 	rightPane := container.NewVBox(
+		container.NewHScroll(tabBar),
 		requestRow,
+		bodyIgnoredWarning,
 		saveLoadRow,
 		requestTabs,
 		widget.NewSeparator(),
 		responseSection,
+		console,
 	)
 
 	// Main layout: horizontal split, sidebar and right pane
+	rightScroll := container.NewVScroll(rightPane)
 	split := container.NewHSplit(
 		container.NewVBox(sidebar),
-		container.NewVScroll(rightPane),
+		rightScroll,
 	)
 	split.Offset = 0.11 // Sidebar width smaller than right pane
 	w.SetContent(split)
+
+	// Keyboard shortcuts; a focused text field keeps shortcuts to itself, so these
+	// apply when no field has focus (e.g. after sending or clicking the list)
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { newTab() })
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift},
+		func(fyne.Shortcut) { createNewCollection() })
+
+	// Ctrl+Down jumps to the response body, Ctrl+Up back to the request body (or the
+	// URL when the method sends none). The editors pass these on instead of keeping them.
+	focusResponse := func() {
+		responseTabs.SelectIndex(0)
+		rightScroll.Offset = fyne.NewPos(0, responseSection.Position().Y)
+		rightScroll.Refresh()
+		w.Canvas().Focus(jsonResponse)
+	}
+	focusRequest := func() {
+		rightScroll.ScrollToTop()
+		if methodSendsBody(methodSelect.Selected) && bodyEntry.Visible() {
+			requestTabs.Select(bodyTab)
+			w.Canvas().Focus(bodyEntry)
+			return
+		}
+		w.Canvas().Focus(urlEntry)
+	}
+	jumpShortcut := func(shortcut fyne.Shortcut) bool {
+		switch {
+		case isCustomShortcut(shortcut, fyne.KeyDown, fyne.KeyModifierShortcutDefault):
+			focusResponse()
+		case isCustomShortcut(shortcut, fyne.KeyUp, fyne.KeyModifierShortcutDefault):
+			focusRequest()
+		default:
+			return false
+		}
+		return true
+	}
+	for _, entry := range []*shortcutEntry{urlEntry, headersEntry, bodyEntry, jsonResponse} {
+		entry.onShortcut = jumpShortcut
+	}
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyDown, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { focusResponse() })
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyUp, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { focusRequest() })
 	w.Resize(fyne.NewSize(2000, 1200))
 	urlEntry.Resize(fyne.NewSize(900, urlEntry.MinSize().Height)) // Set width after window is created
 	w.ShowAndRun()
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+)
+
+// Default names for saved requests, built from a template such as "{{method}} {{path}}".
+// {{method}}, {{host}}, {{path}} and {{url}} are replaced; anything else is kept as typed.
+
+const defaultNamingTemplate = "{{method}} {{path}}"
+
+// splitRequestURL separates the host and path of a URL that may still contain
+// {{var}} references, which url.Parse would reject or misread
+func splitRequestURL(rawURL string) (host, path string) {
+	base, _, _ := splitURL(strings.TrimSpace(rawURL))
+	if i := strings.Index(base, "://"); i != -1 {
+		base = base[i+3:]
+	}
+	host, path, found := strings.Cut(base, "/")
+	if found {
+		path = "/" + path
+	}
+	return host, path
+}
+
+// requestName fills in the naming template for r. The URL is used when the
+// template produces nothing, so a request is never saved without a name.
+func requestName(template string, r APIRequest) string {
+	if strings.TrimSpace(template) == "" {
+		template = defaultNamingTemplate
+	}
+	host, path := splitRequestURL(r.URL)
+	if path == "" {
+		path = "/"
+	}
+	name := strings.NewReplacer(
+		"{{method}}", r.Method,
+		"{{host}}", host,
+		"{{path}}", path,
+		"{{url}}", r.URL,
+	).Replace(template)
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return r.URL
+	}
+	return name
+}
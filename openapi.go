@@ -0,0 +1,727 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Import of OpenAPI 3 and Swagger 2 specs, in JSON or YAML, as a collection with a
+// request per operation. Operations are grouped in folders by their first tag. Path
+// parameters stay as {id} placeholders in the URL, and a request body is filled with
+// an example built from its schema.
+
+// Methods in the order their operations are listed
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+
+// openAPIMaxDepth limits how many $refs are followed in a row, and how deep example bodies nest
+const openAPIMaxDepth = 8
+
+// Base URL of specs that don't name a server
+const openAPIBaseURLVar = "baseUrl"
+
+type openAPISpec struct {
+	doc map[string]interface{}
+	// Swagger 2.0 rather than OpenAPI 3
+	swagger bool
+}
+
+// parseOpenAPI reads a spec into a collection named after its title
+func parseOpenAPI(data []byte) (Collection, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return Collection{}, fmt.Errorf("not JSON or YAML: %v", err)
+	}
+	doc, ok := normalizeYAML(raw).(map[string]interface{})
+	if !ok {
+		return Collection{}, fmt.Errorf("not an OpenAPI document")
+	}
+	spec := openAPISpec{doc: doc}
+	if version, ok := doc["swagger"].(string); ok && strings.HasPrefix(version, "2") {
+		spec.swagger = true
+	} else if _, ok := doc["openapi"]; !ok {
+		return Collection{}, fmt.Errorf("missing the \"openapi\" or \"swagger\" version field")
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	if len(paths) == 0 {
+		return Collection{}, fmt.Errorf("the spec has no paths")
+	}
+	col := Collection{Name: "OpenAPI Import"}
+	if title := stringAt(doc, "info", "title"); title != "" {
+		col.Name = title
+	}
+	folders := map[string]int{}
+	usesBaseURL := false
+	pathNames := make([]string, 0, len(paths))
+	for p := range paths {
+		pathNames = append(pathNames, p)
+	}
+	sort.Strings(pathNames)
+	for _, p := range pathNames {
+		item, _ := spec.resolve(paths[p]).(map[string]interface{})
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			req, base := spec.request(p, method, item, op)
+			usesBaseURL = usesBaseURL || base
+			tags, _ := op["tags"].([]interface{})
+			tag, _ := firstOf(tags).(string)
+			if tag == "" {
+				col.Requests = append(col.Requests, req)
+				continue
+			}
+			idx, ok := folders[tag]
+			if !ok {
+				idx = len(col.Folders)
+				folders[tag] = idx
+				col.Folders = append(col.Folders, Folder{Name: tag})
+			}
+			col.Folders[idx].Requests = append(col.Folders[idx].Requests, req)
+		}
+	}
+	if len(col.Requests) == 0 && len(col.Folders) == 0 {
+		return Collection{}, fmt.Errorf("the spec has no operations")
+	}
+	if usesBaseURL {
+		col.Variables = map[string]string{openAPIBaseURLVar: ""}
+	}
+	return col, nil
+}
+
+// normalizeYAML turns maps with non-string keys, like response codes, into string maps
+// so the document reads the same as one decoded from JSON
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = normalizeYAML(val)
+		}
+		return t
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeYAML(t[i])
+		}
+	}
+	return v
+}
+
+func stringAt(v interface{}, keys ...string) string {
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[k]
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func firstOf(list []interface{}) interface{} {
+	if len(list) == 0 {
+		return nil
+	}
+	return list[0]
+}
+
+// resolve follows a local $ref like #/components/schemas/User; other values are returned as they are
+func (s openAPISpec) resolve(v interface{}) interface{} {
+	for i := 0; i < openAPIMaxDepth; i++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return v
+		}
+		var target interface{} = s.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			obj, _ := target.(map[string]interface{})
+			target = obj[part]
+		}
+		if target == nil {
+			return nil
+		}
+		v = target
+	}
+	return v
+}
+
+// baseURL returns the first server of the operation, path or document, and whether
+// it had to fall back to the {{baseUrl}} variable
+func (s openAPISpec) baseURL(item, op map[string]interface{}) (string, bool) {
+	if s.swagger {
+		host := stringAt(s.doc, "host")
+		basePath := strings.TrimSuffix(stringAt(s.doc, "basePath"), "/")
+		if host == "" {
+			return "{{" + openAPIBaseURLVar + "}}" + basePath, true
+		}
+		scheme := "https"
+		if schemes, _ := s.doc["schemes"].([]interface{}); len(schemes) > 0 {
+			if first, ok := schemes[0].(string); ok {
+				scheme = first
+			}
+		}
+		return scheme + "://" + host + basePath, false
+	}
+	for _, holder := range []map[string]interface{}{op, item, s.doc} {
+		servers, _ := holder["servers"].([]interface{})
+		server, ok := firstOf(servers).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		u := stringAt(server, "url")
+		// Server variables take their default values
+		vars, _ := server["variables"].(map[string]interface{})
+		for name, v := range vars {
+			u = strings.ReplaceAll(u, "{"+name+"}", stringAt(v, "default"))
+		}
+		u = strings.TrimSuffix(u, "/")
+		if !strings.Contains(u, "://") {
+			return "{{" + openAPIBaseURLVar + "}}" + u, true
+		}
+		return u, false
+	}
+	return "{{" + openAPIBaseURLVar + "}}", true
+}
+
+// parameters returns the operation's parameters after those of its path that it doesn't override
+func (s openAPISpec) parameters(item, op map[string]interface{}) []map[string]interface{} {
+	params := []map[string]interface{}{}
+	seen := map[string]bool{}
+	for _, holder := range []map[string]interface{}{op, item} {
+		list, _ := holder["parameters"].([]interface{})
+		for _, p := range list {
+			param, ok := s.resolve(p).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := stringAt(param, "in") + ":" + stringAt(param, "name")
+			if !seen[key] {
+				seen[key] = true
+				params = append(params, param)
+			}
+		}
+	}
+	return params
+}
+
+// request builds the request of one operation; base reports whether its URL uses {{baseUrl}}
+func (s openAPISpec) request(path, method string, item, op map[string]interface{}) (APIRequest, bool) {
+	prefix, base := s.baseURL(item, op)
+	req := newAPIRequest(strings.ToUpper(method), prefix+path)
+	switch {
+	case stringAt(op, "summary") != "":
+		req.Name = stringAt(op, "summary")
+	case stringAt(op, "operationId") != "":
+		req.Name = stringAt(op, "operationId")
+	default:
+		req.Name = strings.ToUpper(method) + " " + path
+	}
+	query := url.Values{}
+	var formParams []map[string]interface{}
+	for _, param := range s.parameters(item, op) {
+		name := stringAt(param, "name")
+		switch stringAt(param, "in") {
+		case "header":
+			req.Headers[name] = s.parameterValue(param)
+		case "query":
+			if required, _ := param["required"].(bool); required {
+				query.Set(name, s.parameterValue(param))
+			}
+		case "body":
+			// Swagger 2 body parameter
+			if body, err := json.MarshalIndent(s.example(param["schema"]), "", "  "); err == nil {
+				req.Body = string(body)
+				req.Headers["Content-Type"] = "application/json"
+			}
+		case "formData":
+			formParams = append(formParams, param)
+		}
+	}
+	if len(query) > 0 {
+		req.URL += "?" + query.Encode()
+	}
+	if len(formParams) > 0 {
+		// The form modes set their own Content-Type
+		req.Body = ""
+		delete(req.Headers, "Content-Type")
+		req.BodyMode = bodyModeURLEncoded
+		for _, param := range formParams {
+			field := FormField{Key: stringAt(param, "name"), Value: s.parameterValue(param)}
+			if stringAt(param, "type") == "file" {
+				req.BodyMode = bodyModeMultipart
+				field.IsFile = true
+			}
+			req.FormFields = append(req.FormFields, field)
+		}
+	}
+	if body, ok := s.resolve(op["requestBody"]).(map[string]interface{}); ok {
+		content, _ := body["content"].(map[string]interface{})
+		s.applyContent(&req, content)
+	}
+	return req, base
+}
+
+// applyContent sets the body from the request body's media types, preferring JSON
+func (s openAPISpec) applyContent(req *APIRequest, content map[string]interface{}) {
+	if len(content) == 0 {
+		return
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	mediaType := types[0]
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			mediaType = t
+			break
+		}
+	}
+	media, _ := content[mediaType].(map[string]interface{})
+	req.Headers["Content-Type"] = mediaType
+	example, ok := media["example"]
+	if !ok {
+		example = s.example(media["schema"])
+	}
+	switch {
+	case strings.Contains(mediaType, "json"):
+		if body, err := json.MarshalIndent(example, "", "  "); err == nil {
+			req.Body = string(body)
+		}
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		// The form modes set their own Content-Type
+		delete(req.Headers, "Content-Type")
+		req.BodyMode = bodyModeURLEncoded
+		if mediaType == "multipart/form-data" {
+			req.BodyMode = bodyModeMultipart
+		}
+		fields, _ := example.(map[string]interface{})
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			req.FormFields = append(req.FormFields, FormField{Key: k, Value: fmt.Sprint(fields[k])})
+		}
+	default:
+		if text, ok := example.(string); ok {
+			req.Body = text
+		}
+	}
+}
+
+// parameterValue is a parameter's example or default, or empty
+func (s openAPISpec) parameterValue(param map[string]interface{}) string {
+	for _, v := range []interface{}{param["example"], param["default"]} {
+		if v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	if schema, ok := s.resolve(param["schema"]).(map[string]interface{}); ok {
+		for _, v := range []interface{}{schema["example"], schema["default"]} {
+			if v != nil {
+				return fmt.Sprint(v)
+			}
+		}
+	}
+	return ""
+}
+
+// example builds a sample value from a schema: its example or default when it has
+// one, otherwise a placeholder of its type, with objects and arrays filled in. A
+// schema that refers back to itself is null the second time round.
+func (s openAPISpec) example(schema interface{}) interface{} {
+	return s.exampleOf(schema, 0, nil)
+}
+
+func (s openAPISpec) exampleOf(schema interface{}, depth int, refs []string) interface{} {
+	if depth > openAPIMaxDepth {
+		return nil
+	}
+	if ref := stringAt(schema, "$ref"); ref != "" {
+		for _, r := range refs {
+			if r == ref {
+				return nil
+			}
+		}
+		refs = append(refs[:len(refs):len(refs)], ref)
+	}
+	m, ok := s.resolve(schema).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, key := range []string{"example", "default"} {
+		if v, ok := m[key]; ok {
+			return v
+		}
+	}
+	if enum, _ := m["enum"].([]interface{}); len(enum) > 0 {
+		return enum[0]
+	}
+	if all, _ := m["allOf"].([]interface{}); len(all) > 0 {
+		merged := map[string]interface{}{}
+		for _, sub := range all {
+			if obj, ok := s.exampleOf(sub, depth+1, refs).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if choices, _ := m[key].([]interface{}); len(choices) > 0 {
+			return s.exampleOf(choices[0], depth+1, refs)
+		}
+	}
+	typ := stringAt(m, "type")
+	if props, ok := m["properties"].(map[string]interface{}); ok || typ == "object" {
+		obj := map[string]interface{}{}
+		for name, prop := range props {
+			obj[name] = s.exampleOf(prop, depth+1, refs)
+		}
+		return obj
+	}
+	switch typ {
+	case "array":
+		item := s.exampleOf(m["items"], depth+1, refs)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		switch stringAt(m, "format") {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	}
+	return nil
+}
+
+// Export of a collection as a minimal OpenAPI 3.0 document: one operation per path
+// and method, with the path, query and header parameters and the sample body of the
+// request that made it. Folders at the top level become tags.
+
+var (
+	// {{var}} and :id tokens in a URL path, both written {var} in OpenAPI
+	openAPIPathVar = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}|:([A-Za-z_][A-Za-z0-9_]*)|\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+	openAPIWords   = regexp.MustCompile(`[A-Za-z0-9]+`)
+)
+
+// Headers described elsewhere in the spec, or set by the client
+var openAPISkippedHeaders = map[string]bool{"content-type": true, "authorization": true, "content-length": true, "host": true}
+
+// splitServer separates the server (scheme and host, or a leading {{var}}) from
+// the path and query of a request URL
+func splitServer(rawURL string) (server, path, query string) {
+	raw, query, _ := splitURL(strings.TrimSpace(rawURL))
+	rest := raw
+	if i := strings.Index(raw, "://"); i != -1 {
+		rest = raw[i+3:]
+	} else if strings.HasPrefix(raw, "{{") {
+		end := strings.Index(raw, "}}")
+		if end == -1 {
+			return "", raw, query
+		}
+		rest = raw[end+2:]
+		if !strings.HasPrefix(rest, "/") && rest != "" {
+			// {{host}}.example.com/...: the variable is only part of the host
+			slash := strings.Index(rest, "/")
+			if slash == -1 {
+				return raw, "/", query
+			}
+			return raw[:end+2+slash], rest[slash:], query
+		}
+		return raw[:end+2], rest, query
+	} else if !strings.HasPrefix(raw, "/") {
+		// Sent with https:// added in front
+		raw = "https://" + raw
+		rest = raw[len("https://"):]
+	}
+	slash := strings.Index(rest, "/")
+	if slash == -1 {
+		return raw, "/", query
+	}
+	return raw[:len(raw)-len(rest)+slash], rest[slash:], query
+}
+
+// openAPIPath rewrites :id and {{var}} path tokens as {id} and {var}, returning the names
+func openAPIPath(path string) (string, []string) {
+	names := []string{}
+	seen := map[string]bool{}
+	path = openAPIPathVar.ReplaceAllStringFunc(path, func(token string) string {
+		m := openAPIPathVar.FindStringSubmatch(token)
+		name := m[1] + m[2] + m[3]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		return "{" + name + "}"
+	})
+	if path == "" {
+		path = "/"
+	}
+	return path, names
+}
+
+// openAPIServer turns {{var}} in a server URL into an OpenAPI server variable,
+// defaulting to the collection's value of the variable
+func openAPIServer(server string, vars map[string]string) map[string]interface{} {
+	variables := map[string]interface{}{}
+	u := openAPIPathVar.ReplaceAllStringFunc(server, func(token string) string {
+		m := openAPIPathVar.FindStringSubmatch(token)
+		if m[1] == "" {
+			return token
+		}
+		value := vars[m[1]]
+		if value == "" {
+			value = "https://api.example.com"
+		}
+		variables[m[1]] = map[string]interface{}{"default": value}
+		return "{" + m[1] + "}"
+	})
+	result := map[string]interface{}{"url": u}
+	if len(variables) > 0 {
+		result["variables"] = variables
+	}
+	return result
+}
+
+// schemaFromExample describes the type of a JSON value
+func schemaFromExample(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		props := map[string]interface{}{}
+		for k, val := range t {
+			props[k] = schemaFromExample(val)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array", "items": map[string]interface{}{}}
+		if len(t) > 0 {
+			schema["items"] = schemaFromExample(t[0])
+		}
+		return schema
+	case float64:
+		if t == float64(int64(t)) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+// operationID makes a camelCase ID from a request name, unique among used
+func operationID(name string, used map[string]bool) string {
+	id := ""
+	for i, word := range openAPIWords.FindAllString(name, -1) {
+		if i == 0 {
+			id += strings.ToLower(word[:1]) + word[1:]
+		} else {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	if id == "" {
+		id = "operation"
+	}
+	unique := id
+	for n := 2; used[unique]; n++ {
+		unique = fmt.Sprintf("%s%d", id, n)
+	}
+	used[unique] = true
+	return unique
+}
+
+// openAPIRequestBody describes the body of r with the body itself as the example
+func openAPIRequestBody(r APIRequest) map[string]interface{} {
+	content := map[string]interface{}{}
+	switch r.BodyMode {
+	case bodyModeMultipart, bodyModeURLEncoded:
+		if len(r.FormFields) == 0 {
+			return nil
+		}
+		props := map[string]interface{}{}
+		for _, f := range r.FormFields {
+			prop := map[string]interface{}{"type": "string"}
+			if f.IsFile {
+				prop["format"] = "binary"
+			} else if f.Value != "" {
+				prop["example"] = f.Value
+			}
+			props[f.Key] = prop
+		}
+		mediaType := "application/x-www-form-urlencoded"
+		if r.BodyMode == bodyModeMultipart {
+			mediaType = "multipart/form-data"
+		}
+		content[mediaType] = map[string]interface{}{"schema": map[string]interface{}{"type": "object", "properties": props}}
+	default:
+		if strings.TrimSpace(r.Body) == "" {
+			return nil
+		}
+		mediaType := ""
+		for k, v := range r.Headers {
+			if strings.EqualFold(k, "Content-Type") {
+				mediaType = strings.TrimSpace(strings.Split(v, ";")[0])
+			}
+		}
+		var example interface{}
+		if err := json.Unmarshal([]byte(r.Body), &example); err == nil {
+			if mediaType == "" {
+				mediaType = "application/json"
+			}
+			content[mediaType] = map[string]interface{}{"schema": schemaFromExample(example), "example": example}
+		} else {
+			if mediaType == "" {
+				mediaType = "text/plain"
+			}
+			content[mediaType] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}, "example": r.Body}
+		}
+	}
+	return map[string]interface{}{"content": content}
+}
+
+// exampleValue leaves out values that are only variable references
+func exampleValue(param map[string]interface{}, value string) {
+	if value != "" && !strings.Contains(value, "{{") {
+		param["example"] = value
+	}
+}
+
+// exportOpenAPI builds the document for a collection. Requests with the same path and
+// method as an earlier one are left out; their names are returned.
+func exportOpenAPI(c *Collection) ([]byte, []string, error) {
+	paths := map[string]interface{}{}
+	servers := []interface{}{}
+	serverIdx := map[string]int{}
+	tags := []interface{}{}
+	tagged := map[string]bool{}
+	usedIDs := map[string]bool{}
+	skipped := []string{}
+	requests := c.allRequests()
+	for i, s := range c.slots() {
+		r := requests[i]
+		server, rawPath, query := splitServer(r.URL)
+		path, pathVars := openAPIPath(rawPath)
+		method := strings.ToLower(r.Method)
+		if method == "" {
+			method = "get"
+		}
+		item, _ := paths[path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		if _, dup := item[method]; dup {
+			skipped = append(skipped, r.Name)
+			continue
+		}
+		op := map[string]interface{}{
+			"summary":     r.Name,
+			"operationId": operationID(r.Name, usedIDs),
+			"responses":   map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+		}
+		if len(s.folder) > 0 {
+			tag := c.Folders[s.folder[0]].Name
+			op["tags"] = []string{tag}
+			if !tagged[tag] {
+				tagged[tag] = true
+				tags = append(tags, map[string]interface{}{"name": tag})
+			}
+		}
+		params := []interface{}{}
+		for _, name := range pathVars {
+			params = append(params, map[string]interface{}{"name": name, "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}})
+		}
+		if values, err := url.ParseQuery(query); err == nil {
+			names := make([]string, 0, len(values))
+			for name := range values {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				param := map[string]interface{}{"name": name, "in": "query", "schema": map[string]interface{}{"type": "string"}}
+				exampleValue(param, values.Get(name))
+				params = append(params, param)
+			}
+		}
+		headerNames := make([]string, 0, len(r.Headers))
+		for name := range r.Headers {
+			if !openAPISkippedHeaders[strings.ToLower(name)] {
+				headerNames = append(headerNames, name)
+			}
+		}
+		sort.Strings(headerNames)
+		for _, name := range headerNames {
+			param := map[string]interface{}{"name": name, "in": "header", "schema": map[string]interface{}{"type": "string"}}
+			exampleValue(param, r.Headers[name])
+			params = append(params, param)
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if body := openAPIRequestBody(r); body != nil {
+			op["requestBody"] = body
+		}
+		if server != "" {
+			idx, ok := serverIdx[server]
+			if !ok {
+				idx = len(servers)
+				serverIdx[server] = idx
+				servers = append(servers, openAPIServer(server, c.Variables))
+			}
+			// The first server is the document's; operations elsewhere name their own
+			if idx > 0 {
+				op["servers"] = []interface{}{servers[idx]}
+			}
+		}
+		item[method] = op
+	}
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": c.Name, "version": "1.0.0"},
+		"paths":   paths,
+	}
+	if len(servers) > 0 {
+		doc["servers"] = servers
+	}
+	if len(tags) > 0 {
+		doc["tags"] = tags
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	return data, skipped, err
+}
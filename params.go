@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Query parameters of the URL, edited as a key/value table in the Params tab.
+// The query is split by hand rather than with url.ParseQuery so order, repeated
+// keys and {{var}} references survive a round trip.
+
+type queryParam struct {
+	Key   string
+	Value string
+}
+
+// splitURL separates rawURL into the part before the query, the query, and the fragment (with its '#')
+func splitURL(rawURL string) (base, query, fragment string) {
+	if i := strings.Index(rawURL, "#"); i != -1 {
+		rawURL, fragment = rawURL[:i], rawURL[i:]
+	}
+	base, query, _ = strings.Cut(rawURL, "?")
+	return base, query, fragment
+}
+
+func unescapeQueryComponent(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// escapeQueryComponent query-escapes s but leaves {{var}} references as typed
+func escapeQueryComponent(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range variablePattern.FindAllStringIndex(s, -1) {
+		sb.WriteString(url.QueryEscape(s[last:loc[0]]))
+		sb.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(url.QueryEscape(s[last:]))
+	return sb.String()
+}
+
+// parseQueryParams returns the decoded query parameters of rawURL in order
+func parseQueryParams(rawURL string) []queryParam {
+	_, query, _ := splitURL(rawURL)
+	params := []queryParam{}
+	if query == "" {
+		return params
+	}
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		params = append(params, queryParam{Key: unescapeQueryComponent(key), Value: unescapeQueryComponent(value)})
+	}
+	return params
+}
+
+// withQueryParams replaces the query of rawURL with params, encoding keys and values.
+// Rows with neither key nor value are skipped.
+func withQueryParams(rawURL string, params []queryParam) string {
+	base, _, fragment := splitURL(rawURL)
+	pairs := []string{}
+	for _, p := range params {
+		if p.Key == "" && p.Value == "" {
+			continue
+		}
+		pairs = append(pairs, escapeQueryComponent(p.Key)+"="+escapeQueryComponent(p.Value))
+	}
+	if len(pairs) == 0 {
+		return base + fragment
+	}
+	return base + "?" + strings.Join(pairs, "&") + fragment
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Patch builder helpers for JSON Patch (RFC 6902) and JSON Merge Patch (RFC 7396)
+
+const (
+	patchTypeJSONPatch  = "JSON Patch"
+	patchTypeMergePatch = "JSON Merge Patch"
+)
+
+var jsonPatchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+type patchRow struct {
+	Op    string
+	Path  string
+	Value string
+}
+
+// patchContentType returns the Content-Type to send for the given patch type
+func patchContentType(patchType string) string {
+	if patchType == patchTypeMergePatch {
+		return "application/merge-patch+json"
+	}
+	return "application/json-patch+json"
+}
+
+// Values that parse as JSON are sent as-is, anything else is treated as a string
+func parsePatchValue(value string) interface{} {
+	trimmed := strings.TrimSpace(value)
+	var v interface{}
+	if trimmed != "" && json.Unmarshal([]byte(trimmed), &v) == nil {
+		return v
+	}
+	return value
+}
+
+func buildJSONPatch(rows []patchRow) ([]byte, error) {
+	ops := []map[string]interface{}{}
+	for i, row := range rows {
+		if strings.TrimSpace(row.Path) == "" {
+			continue
+		}
+		if !strings.HasPrefix(row.Path, "/") {
+			return nil, fmt.Errorf("Row %d: path must start with '/'", i+1)
+		}
+		op := map[string]interface{}{"op": row.Op, "path": row.Path}
+		switch row.Op {
+		case "remove":
+		case "move", "copy":
+			if !strings.HasPrefix(row.Value, "/") {
+				return nil, fmt.Errorf("Row %d: '%s' needs a 'from' path starting with '/'", i+1, row.Op)
+			}
+			op["from"] = row.Value
+		case "add", "replace", "test":
+			op["value"] = parsePatchValue(row.Value)
+		default:
+			return nil, fmt.Errorf("Row %d: unknown op '%s'", i+1, row.Op)
+		}
+		ops = append(ops, op)
+	}
+	return json.MarshalIndent(ops, "", "    ")
+}
+
+func buildMergePatch(rows []patchRow) ([]byte, error) {
+	doc := map[string]interface{}{}
+	for i, row := range rows {
+		path := strings.Trim(strings.TrimSpace(row.Path), "/")
+		if path == "" {
+			continue
+		}
+		keys := strings.Split(path, "/")
+		node := doc
+		for j, key := range keys {
+			key = strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+			if j == len(keys)-1 {
+				// A JSON null removes the member on the server side
+				node[key] = parsePatchValue(row.Value)
+				break
+			}
+			child, ok := node[key].(map[string]interface{})
+			if !ok {
+				if _, exists := node[key]; exists {
+					return nil, fmt.Errorf("Row %d: '%s' is already set to a non-object value", i+1, key)
+				}
+				child = map[string]interface{}{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// setHeaderLine replaces (or appends) a header in the raw headers text, keeping other lines intact
+func setHeaderLine(headerStr, key, value string) string {
+	lines := []string{}
+	for _, line := range strings.Split(headerStr, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), key) {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, key+": "+value)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Postman collection v2.1 items. An item with a request is a request; one without
+// is a folder, holding further items.
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Request *postmanRequest `json:"request,omitempty"`
+	Item    []postmanItem   `json:"item,omitempty"`
+}
+
+// MarshalJSON always writes the items of a folder, even none, so Postman doesn't read it as a request
+func (p postmanItem) MarshalJSON() ([]byte, error) {
+	if p.Request != nil {
+		return json.Marshal(struct {
+			Name    string          `json:"name"`
+			Request *postmanRequest `json:"request"`
+		}{p.Name, p.Request})
+	}
+	items := p.Item
+	if items == nil {
+		items = []postmanItem{}
+	}
+	return json.Marshal(struct {
+		Name string        `json:"name"`
+		Item []postmanItem `json:"item"`
+	}{p.Name, items})
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	URL    interface{}       `json:"url"`
+	Header []postmanKeyValue `json:"header"`
+	Body   postmanBody       `json:"body"`
+	Auth   *postmanAuth      `json:"auth,omitempty"`
+}
+
+func (p postmanRequest) toAPIRequest(name string) APIRequest {
+	headers := map[string]string{}
+	for _, h := range p.Header {
+		if prev, ok := headers[h.Key]; ok {
+			headers[h.Key] = joinHeaderValues([]string{prev, h.Value})
+		} else {
+			headers[h.Key] = h.Value
+		}
+	}
+	urlStr := ""
+	switch v := p.URL.(type) {
+	case string:
+		urlStr = v
+	case map[string]interface{}:
+		if raw, ok := v["raw"].(string); ok {
+			urlStr = raw
+		}
+	}
+	req := newAPIRequest(p.Method, urlStr)
+	req.Name = name
+	req.Headers = headers
+	p.Body.applyTo(&req)
+	req.Auth = p.Auth.toAuth()
+	// A literal Authorization header moves to the Auth tab
+	for k, v := range headers {
+		if req.Auth == nil && strings.EqualFold(k, "Authorization") {
+			if auth, ok := authFromHeader(v); ok {
+				req.Auth = auth
+				delete(headers, k)
+			}
+		}
+	}
+	return req
+}
+
+func toPostmanRequest(r APIRequest) *postmanRequest {
+	header := []postmanKeyValue{}
+	for k, v := range r.Headers {
+		for _, value := range headerValues(v) {
+			header = append(header, postmanKeyValue{Key: k, Value: value})
+		}
+	}
+	return &postmanRequest{Method: r.Method, URL: r.URL, Header: header, Body: toPostmanBody(r), Auth: toPostmanAuth(r.Auth)}
+}
+
+// fromPostmanItems returns the requests and folders of a list of items
+func fromPostmanItems(items []postmanItem) ([]APIRequest, []Folder) {
+	var requests []APIRequest
+	var folders []Folder
+	for _, item := range items {
+		if item.Request == nil {
+			folder := Folder{Name: item.Name}
+			folder.Requests, folder.Folders = fromPostmanItems(item.Item)
+			folders = append(folders, folder)
+			continue
+		}
+		requests = append(requests, item.Request.toAPIRequest(item.Name))
+	}
+	return requests, folders
+}
+
+// toPostmanItems lists folders before requests, the order they have in the app
+func toPostmanItems(requests []APIRequest, folders []Folder) []postmanItem {
+	items := []postmanItem{}
+	for _, f := range folders {
+		items = append(items, postmanItem{Name: f.Name, Item: toPostmanItems(f.Requests, f.Folders)})
+	}
+	for _, r := range requests {
+		items = append(items, postmanItem{Name: r.Name, Request: toPostmanRequest(r)})
+	}
+	return items
+}
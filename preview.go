@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	// Decoders for image.Decode
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Rendering of responses in the Preview tab
+
+// isImageContentType reports whether a Content-Type names an image, e.g. "image/png"
+func isImageContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasPrefix(mediaType, "image/")
+}
+
+// decodeImage decodes a PNG, JPEG or GIF body and returns the image with its format name
+func decodeImage(body []byte) (image.Image, string, error) {
+	return image.Decode(bytes.NewReader(body))
+}
+
+// isHTMLContentType reports whether a Content-Type is text/html
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/html"
+}
+
+// Elements whose content is never shown
+var hiddenHTMLElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+}
+
+// Elements that start and end a paragraph of their own
+var blockHTMLElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Header: true,
+	atom.Footer: true, atom.Main: true, atom.Nav: true, atom.Aside: true, atom.Form: true,
+	atom.Blockquote: true, atom.Table: true, atom.Tr: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "#", `\#`)
+
+// htmlToMarkdown converts an HTML document to Markdown for a rich text rendering:
+// headings, paragraphs, links, lists, emphasis and code are kept, scripts and styles dropped.
+// Table rows become lines with cells separated by " | ".
+func htmlToMarkdown(body []byte) string {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return markdownEscaper.Replace(string(body))
+	}
+	var sb strings.Builder
+	// Ends the current paragraph unless the output already ends with one
+	paragraph := func() {
+		text := sb.String()
+		if text != "" && !strings.HasSuffix(text, "\n\n") {
+			if strings.HasSuffix(text, "\n") {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+	}
+	attr := func(n *html.Node, key string) string {
+		for _, a := range n.Attr {
+			if a.Key == key {
+				return a.Val
+			}
+		}
+		return ""
+	}
+	var listDepth int
+	var walk func(n *html.Node, pre bool)
+	children := func(n *html.Node, pre bool) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, pre)
+		}
+	}
+	walk = func(n *html.Node, pre bool) {
+		switch n.Type {
+		case html.TextNode:
+			if pre {
+				sb.WriteString(n.Data)
+				return
+			}
+			text := strings.Join(strings.Fields(n.Data), " ")
+			if text == "" {
+				return
+			}
+			// Keep the space between inline pieces such as "see <a>this</a> page"
+			if unicode.IsSpace(rune(n.Data[0])) && !strings.HasSuffix(sb.String(), " ") && !strings.HasSuffix(sb.String(), "\n") {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(markdownEscaper.Replace(text))
+			if unicode.IsSpace(rune(n.Data[len(n.Data)-1])) {
+				sb.WriteString(" ")
+			}
+			return
+		case html.DocumentNode:
+			children(n, pre)
+			return
+		case html.ElementNode:
+		default:
+			return
+		}
+		if hiddenHTMLElements[n.DataAtom] {
+			return
+		}
+		switch n.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			paragraph()
+			sb.WriteString(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+			children(n, pre)
+			paragraph()
+		case atom.Br:
+			sb.WriteString("\n\n")
+		case atom.Hr:
+			paragraph()
+			sb.WriteString("---\n\n")
+		case atom.Strong, atom.B:
+			sb.WriteString("**")
+			children(n, pre)
+			sb.WriteString("**")
+		case atom.Em, atom.I:
+			sb.WriteString("*")
+			children(n, pre)
+			sb.WriteString("*")
+		case atom.Code:
+			if pre {
+				children(n, pre)
+				break
+			}
+			sb.WriteString("`")
+			children(n, true)
+			sb.WriteString("`")
+		case atom.Pre:
+			paragraph()
+			sb.WriteString("```\n")
+			children(n, true)
+			sb.WriteString("\n```\n\n")
+		case atom.A:
+			href := attr(n, "href")
+			if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+				children(n, pre)
+				break
+			}
+			sb.WriteString("[")
+			children(n, pre)
+			sb.WriteString("](" + strings.ReplaceAll(href, " ", "%20") + ")")
+		case atom.Img:
+			if alt := attr(n, "alt"); alt != "" {
+				sb.WriteString("[image: " + markdownEscaper.Replace(alt) + "]")
+			}
+		case atom.Ul, atom.Ol:
+			if listDepth == 0 {
+				paragraph()
+			} else if !strings.HasSuffix(sb.String(), "\n") {
+				// A nested list starts on the line after its parent item
+				sb.WriteString("\n")
+			}
+			listDepth++
+			number := 0
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+					continue
+				}
+				number++
+				sb.WriteString(strings.Repeat("  ", listDepth-1))
+				if n.DataAtom == atom.Ol {
+					sb.WriteString(strconv.Itoa(number) + ". ")
+				} else {
+					sb.WriteString("- ")
+				}
+				children(c, pre)
+				if !strings.HasSuffix(sb.String(), "\n") {
+					sb.WriteString("\n")
+				}
+			}
+			listDepth--
+			if listDepth == 0 {
+				paragraph()
+			}
+		case atom.Td, atom.Th:
+			if n.PrevSibling != nil {
+				sb.WriteString(" | ")
+			}
+			children(n, pre)
+		default:
+			block := blockHTMLElements[n.DataAtom]
+			if block {
+				paragraph()
+			}
+			children(n, pre)
+			if block {
+				paragraph()
+			}
+		}
+	}
+	walk(doc, false)
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Discovery of the methods an endpoint accepts. OPTIONS is asked first; methods
+// are then tried one by one, and a 405 or 501 answer means a method is not allowed.
+
+// Methods tried by default, which should not change anything on the server
+var safeProbeMethods = []string{"GET", "HEAD"}
+
+// Methods tried only when asked, since the server may act on them
+var unsafeProbeMethods = []string{"POST", "PUT", "PATCH", "DELETE"}
+
+type methodProbe struct {
+	Method  string
+	Status  string
+	Allowed bool
+	Err     error
+}
+
+type probeReport struct {
+	// Methods listed in the Allow (or CORS Allow-Methods) header of the OPTIONS response
+	Advertised []string
+	Probes     []methodProbe
+}
+
+// parseAllowHeader splits an Allow header value such as "GET, HEAD, POST" into upper-case methods
+func parseAllowHeader(values ...string) []string {
+	seen := map[string]bool{}
+	methods := []string{}
+	for _, value := range values {
+		for _, m := range strings.Split(value, ",") {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if m != "" && !seen[m] {
+				seen[m] = true
+				methods = append(methods, m)
+			}
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// probeMethods sends r with each method in turn, without a body, and reports which were accepted
+func probeMethods(r APIRequest, includeUnsafe bool, jar http.CookieJar) probeReport {
+	report := probeReport{}
+	send := func(method string) (*exchangeResult, error) {
+		probe := r
+		probe.Method = method
+		probe.Body, probe.BodyMode, probe.FormFields = "", bodyModeRaw, nil
+		return executeRequest(probe, jar)
+	}
+	methods := append([]string{"OPTIONS"}, safeProbeMethods...)
+	if includeUnsafe {
+		methods = append(methods, unsafeProbeMethods...)
+	}
+	for _, method := range methods {
+		res, err := send(method)
+		if err != nil {
+			report.Probes = append(report.Probes, methodProbe{Method: method, Err: err})
+			continue
+		}
+		allowed := res.StatusCode != http.StatusMethodNotAllowed && res.StatusCode != http.StatusNotImplemented
+		report.Probes = append(report.Probes, methodProbe{Method: method, Status: res.Status, Allowed: allowed})
+		if method == "OPTIONS" {
+			report.Advertised = parseAllowHeader(append(res.Header.Values("Allow"), res.Header.Values("Access-Control-Allow-Methods")...)...)
+		}
+	}
+	return report
+}
+
+// summary lists the advertised methods and the outcome of each attempt
+func (p probeReport) summary() string {
+	var sb strings.Builder
+	if len(p.Advertised) > 0 {
+		sb.WriteString("Allow header: " + strings.Join(p.Advertised, ", ") + "\n\n")
+	} else {
+		sb.WriteString("The OPTIONS response had no Allow header.\n\n")
+	}
+	accepted := []string{}
+	for _, probe := range p.Probes {
+		switch {
+		case probe.Err != nil:
+			fmt.Fprintf(&sb, "⚠️ %-8s %v\n", probe.Method, probe.Err)
+		case probe.Allowed:
+			fmt.Fprintf(&sb, "✅ %-8s %s\n", probe.Method, probe.Status)
+			accepted = append(accepted, probe.Method)
+		default:
+			fmt.Fprintf(&sb, "❌ %-8s %s\n", probe.Method, probe.Status)
+		}
+	}
+	if len(accepted) > 0 {
+		sb.WriteString("\nAccepted: " + strings.Join(accepted, ", "))
+	}
+	return sb.String()
+}
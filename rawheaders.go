@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Capture of response headers exactly as they came off the wire. resp.Header is a
+// canonicalized map, so casing, order and duplicates are lost by the time it is read.
+
+// Header sections larger than this are cut off; real servers stay far below it
+const maxRawHeaderBytes = 64 * 1024
+
+// rawHeaderRecorder collects the header section of every response read on its connections
+type rawHeaderRecorder struct {
+	mu    sync.Mutex
+	heads []string
+}
+
+func (rec *rawHeaderRecorder) add(head string) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.heads = append(rec.heads, head)
+}
+
+// final returns the header section of the last non-informational (non-1xx) response
+func (rec *rawHeaderRecorder) final() string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for i := len(rec.heads) - 1; i >= 0; i-- {
+		if fields := strings.Fields(rec.heads[i]); len(fields) < 2 || !strings.HasPrefix(fields[1], "1") {
+			return rec.heads[i]
+		}
+	}
+	return ""
+}
+
+// recordingConn copies the bytes read after each request is written until the end of the header section
+type recordingConn struct {
+	net.Conn
+	rec       *rawHeaderRecorder
+	buf       bytes.Buffer
+	capturing bool
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	// A write starts a new request on this connection, so the next bytes read are a new response
+	if !c.capturing {
+		c.capturing = true
+		c.buf.Reset()
+	}
+	return c.Conn.Write(p)
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if c.capturing && n > 0 {
+		c.buf.Write(p[:n])
+		for c.capturing {
+			data := c.buf.Bytes()
+			end := bytes.Index(data, []byte("\r\n\r\n"))
+			if end == -1 {
+				if c.buf.Len() > maxRawHeaderBytes {
+					c.rec.add(string(data[:maxRawHeaderBytes]) + "\n... (truncated)")
+					c.capturing = false
+				}
+				break
+			}
+			head := string(data[:end])
+			c.rec.add(head)
+			rest := append([]byte{}, data[end+4:]...)
+			c.buf.Reset()
+			// Interim 1xx responses are followed by the real one in the same read stream
+			if fields := strings.Fields(head); len(fields) >= 2 && strings.HasPrefix(fields[1], "1") {
+				c.buf.Write(rest)
+				continue
+			}
+			c.capturing = false
+		}
+	}
+	return n, err
+}
+
+// recordRawHeaders makes client record the response header sections for req. HTTP/2 is
+// turned off, since its headers are binary-encoded and lowercased and have no raw text form.
+// It returns nil when req goes through a proxy, where TLS is set up inside the tunnel
+// and the plaintext can't be reached from the dialer. Otherwise the client gets a
+// transport of its own, whose idle connections the caller closes when done.
+func recordRawHeaders(client *http.Client, req *http.Request) *rawHeaderRecorder {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	if transport.Proxy != nil {
+		if proxyURL, err := transport.Proxy(req); err != nil || proxyURL != nil {
+			return nil
+		}
+	}
+	// The client's transport is shared with other requests, which must not be recorded
+	transport = transport.Clone()
+	client.Transport = transport
+	rec := &rawHeaderRecorder{}
+	dial := (&net.Dialer{}).DialContext
+	transport.ForceAttemptHTTP2 = false
+	transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &recordingConn{Conn: conn, rec: rec}, nil
+	}
+	// Record above TLS so the captured bytes are plaintext
+	transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		config := &tls.Config{}
+		if transport.TLSClientConfig != nil {
+			config = transport.TLSClientConfig.Clone()
+		}
+		if config.ServerName == "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			config.ServerName = host
+		}
+		config.NextProtos = []string{"http/1.1"}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return &recordingConn{Conn: tlsConn, rec: rec}, nil
+	}
+	return rec
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Import of raw HTTP/1.x request text, as copied from Burp or written in .http files:
+//
+//	POST /api/users HTTP/1.1
+//	Host: example.com
+//	Content-Type: application/json
+//
+//	{"name": "test"}
+
+// parseRawHTTPRequest parses the request line and headers with http.ReadRequest.
+// Everything after the first blank line is taken as the body, so a stale
+// Content-Length from an edited capture does not cut it short.
+func parseRawHTTPRequest(text string) (APIRequest, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	// Skip leading blank lines and .http-style comments (# or //)
+	for len(lines) > 0 {
+		trimmed := strings.TrimSpace(lines[0])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "//") {
+			break
+		}
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return APIRequest{}, fmt.Errorf("no request line found")
+	}
+	// .http files often leave out the protocol version
+	if fields := strings.Fields(lines[0]); len(fields) == 2 {
+		lines[0] = fields[0] + " " + fields[1] + " HTTP/1.1"
+	}
+	head, body := lines, ""
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			head = lines[:i]
+			body = strings.TrimRight(strings.Join(lines[i+1:], "\n"), "\n")
+			break
+		}
+	}
+	raw := strings.Join(head, "\r\n") + "\r\n\r\n"
+	parsed, err := http.ReadRequest(bufio.NewReader(strings.NewReader(raw)))
+	if err != nil {
+		return APIRequest{}, err
+	}
+
+	target := parsed.URL
+	if !target.IsAbs() {
+		if parsed.Host == "" {
+			return APIRequest{}, fmt.Errorf("the request line has a relative path but there is no Host header")
+		}
+		target.Host = parsed.Host
+		// Captures rarely say which scheme was used; assume TLS unless the port says otherwise
+		target.Scheme = "https"
+		if strings.HasSuffix(parsed.Host, ":80") {
+			target.Scheme = "http"
+		}
+	}
+	req := newAPIRequest(parsed.Method, target.String())
+	if target.IsAbs() && parsed.Host != "" && parsed.Host != target.Host {
+		req.Host = parsed.Host
+	}
+	for k, v := range parsed.Header {
+		// Recomputed when the request is sent
+		if k == "Content-Length" {
+			continue
+		}
+		req.Headers[k] = joinHeaderValues(v)
+	}
+	req.Body = body
+	return req, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Redirects followed on the way to a response. Each request made for a redirect
+// keeps the response that caused it, so the chain can be read back from the final response.
+
+type redirectHop struct {
+	URL      string
+	Status   string
+	Location string
+}
+
+// redirectChain lists the redirect responses before resp, oldest first
+func redirectChain(resp *http.Response) []redirectHop {
+	hops := []redirectHop{}
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		hop := redirectHop{Status: req.Response.Status, Location: req.Response.Header.Get("Location")}
+		if req.Response.Request != nil {
+			hop.URL = req.Response.Request.URL.String()
+		}
+		hops = append([]redirectHop{hop}, hops...)
+	}
+	return hops
+}
+
+// describeRedirects is the redirect section of the headers panel, empty when there
+// were no redirects and the response is not itself an unfollowed redirect
+func describeRedirects(resp *http.Response) string {
+	var sb strings.Builder
+	hops := redirectChain(resp)
+	if len(hops) > 0 {
+		fmt.Fprintf(&sb, "Redirects (%d):\n", len(hops))
+		for i, hop := range hops {
+			fmt.Fprintf(&sb, "  %d. %s %s\n     Location: %s\n", i+1, hop.Status, hop.URL, hop.Location)
+		}
+		fmt.Fprintf(&sb, "  Final: %s %s\n\n", resp.Status, resp.Request.URL)
+	}
+	if location := resp.Header.Get("Location"); location != "" && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		fmt.Fprintf(&sb, "Redirect not followed: %s -> %s\n\n", resp.Status, location)
+	}
+	return sb.String()
+}
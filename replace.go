@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Find-and-replace over the URLs, headers and bodies of a collection's requests
+
+// One field of one request that contains the search text
+type replaceMatch struct {
+	Request string
+	Field   string
+	Count   int
+	Before  string
+	After   string
+}
+
+// replaceInRequests returns copies of requests with every occurrence of find replaced,
+// plus the affected fields for previewing. The input slice is not modified.
+func replaceInRequests(requests []APIRequest, find, replacement string) ([]APIRequest, []replaceMatch) {
+	updated := make([]APIRequest, len(requests))
+	matches := []replaceMatch{}
+	if find == "" {
+		copy(updated, requests)
+		return updated, matches
+	}
+	apply := func(name, field, text string) string {
+		n := strings.Count(text, find)
+		if n == 0 {
+			return text
+		}
+		after := strings.ReplaceAll(text, find, replacement)
+		matches = append(matches, replaceMatch{Request: name, Field: field, Count: n, Before: text, After: after})
+		return after
+	}
+	for i, r := range requests {
+		r.URL = apply(r.Name, "URL", r.URL)
+		keys := make([]string, 0, len(r.Headers))
+		for k := range r.Headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		headers := map[string]string{}
+		for _, k := range keys {
+			line := apply(r.Name, "Header", k+": "+r.Headers[k])
+			if name, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(name) != "" {
+				headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			} else {
+				// The replacement removed the header name; keep the original header
+				headers[k] = r.Headers[k]
+			}
+		}
+		r.Headers = headers
+		r.Body = apply(r.Name, "Body", r.Body)
+		updated[i] = r
+	}
+	return updated, matches
+}
+
+// summary is the preview line for a match; long fields are shown as a count only
+func (m replaceMatch) summary() string {
+	if m.Field == "Body" && (len(m.Before) > 120 || strings.Contains(m.Before, "\n")) {
+		return fmt.Sprintf("%s — Body: %d occurrence(s)", m.Request, m.Count)
+	}
+	return fmt.Sprintf("%s — %s:\n    %s\n  → %s", m.Request, m.Field, m.Before, m.After)
+}
@@ -0,0 +1,541 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Building outgoing HTTP requests from saved request definitions
+
+// Methods sent without a body; anything typed in the body editor is dropped for these
+func methodSendsBody(method string) bool {
+	switch method {
+	case "GET", "DELETE", "HEAD", "OPTIONS":
+		return false
+	}
+	return true
+}
+
+// Body modes; raw (the default) sends the body text as typed
+const (
+	bodyModeRaw        = ""
+	bodyModeNDJSON     = "ndjson"
+	bodyModeFile       = "file"
+	bodyModeMultipart  = "multipart"
+	bodyModeURLEncoded = "urlencoded"
+)
+
+// Body modes in the order they are offered, with their labels
+var bodyModes = []struct{ Mode, Label string }{
+	{bodyModeRaw, "Raw"},
+	{bodyModeNDJSON, "NDJSON"},
+	{bodyModeFile, "File"},
+	{bodyModeMultipart, "Multipart Form"},
+	{bodyModeURLEncoded, "Form URL-Encoded"},
+}
+
+func bodyModeLabels() []string {
+	labels := []string{}
+	for _, m := range bodyModes {
+		labels = append(labels, m.Label)
+	}
+	return labels
+}
+
+func bodyModeLabel(mode string) string {
+	for _, m := range bodyModes {
+		if m.Mode == mode {
+			return m.Label
+		}
+	}
+	return bodyModes[0].Label
+}
+
+func bodyModeFromLabel(label string) string {
+	for _, m := range bodyModes {
+		if m.Label == label {
+			return m.Mode
+		}
+	}
+	return bodyModeRaw
+}
+
+// ndjsonErrors validates each non-blank line of a newline-delimited JSON body on its own
+func ndjsonErrors(body string) []string {
+	problems := []string{}
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", i+1, err))
+		}
+	}
+	return problems
+}
+
+// newFileBodyRequest streams the body from a file. Without a Content-Length the
+// transport falls back to Transfer-Encoding: chunked, which is how chunked uploads are sent.
+func newFileBodyRequest(r APIRequest) (*http.Request, int, error) {
+	f, err := os.Open(r.BodyFile)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	if r.GzipBody {
+		// Compressed size isn't known up front, so compress the whole file in memory
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, 0, err
+		}
+		if data, err = gzipBytes(data); err != nil {
+			return nil, 0, err
+		}
+		req, err := http.NewRequest(r.Method, r.URL, bytes.NewReader(data))
+		return req, len(data), err
+	}
+	req, err := http.NewRequest(r.Method, r.URL, f)
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	if r.ChunkedUpload {
+		req.ContentLength = -1
+	} else {
+		req.ContentLength = info.Size()
+	}
+	return req, int(info.Size()), nil
+}
+
+// buildHTTPRequest turns a (variable-resolved) request into an *http.Request.
+// It also returns the size of the body that will be sent.
+func buildHTTPRequest(r APIRequest) (*http.Request, int, error) {
+	var req *http.Request
+	var err error
+	var reqSize int
+	var formContentType string
+	if !methodSendsBody(r.Method) {
+		req, err = http.NewRequest(r.Method, r.URL, nil)
+		reqSize = 0
+	} else if r.BodyMode == bodyModeFile {
+		req, reqSize, err = newFileBodyRequest(r)
+	} else if r.BodyMode == bodyModeMultipart {
+		req, reqSize, formContentType, err = newMultipartRequest(r)
+	} else {
+		bodyBytes := []byte(r.Body)
+		if r.BodyMode == bodyModeURLEncoded {
+			bodyBytes = []byte(encodeFormFields(r.FormFields))
+		}
+		if r.BodyMode == bodyModeNDJSON {
+			if problems := ndjsonErrors(r.Body); len(problems) > 0 {
+				return nil, 0, fmt.Errorf("invalid NDJSON body:\n%s", strings.Join(problems, "\n"))
+			}
+			// Bulk endpoints such as Elasticsearch require the last line to be terminated too
+			if len(bodyBytes) > 0 && !bytes.HasSuffix(bodyBytes, []byte("\n")) {
+				bodyBytes = append(bodyBytes, '\n')
+			}
+		}
+		if r.GzipBody && len(bodyBytes) > 0 {
+			bodyBytes, err = gzipBytes(bodyBytes)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		req, err = http.NewRequest(r.Method, r.URL, bytes.NewBuffer(bodyBytes))
+		reqSize = len(bodyBytes)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if r.Proxy != "" && r.Proxy != proxyDirect && !validProxyURL(r.Proxy) {
+		return nil, 0, fmt.Errorf("invalid proxy URL %q", r.Proxy)
+	}
+	for k, v := range r.Headers {
+		for _, value := range headerValues(v) {
+			req.Header.Add(k, value)
+		}
+	}
+	applyAuth(req, r.Auth)
+	if lang := strings.TrimSpace(r.AcceptLanguage); lang != "" && req.Header.Get("Accept-Language") == "" {
+		req.Header.Set("Accept-Language", lang)
+	}
+	if r.BodyMode == bodyModeNDJSON && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	}
+	if r.BodyMode == bodyModeURLEncoded && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	// The boundary is generated per request, so a typed multipart Content-Type can't carry the right one
+	if formContentType != "" {
+		req.Header.Set("Content-Type", formContentType)
+	}
+	if r.GzipBody && req.Body != nil && reqSize > 0 && r.BodyMode != bodyModeMultipart {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	// The transport ignores a Host entry in req.Header, so the override must go on req.Host
+	if host := strings.TrimSpace(r.Host); host != "" {
+		req.Host = host
+	}
+	return req, reqSize, nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Outcome of a request executed outside the main send flow (flows, runners)
+type exchangeResult struct {
+	StatusCode  int
+	Status      string
+	Header      http.Header
+	Body        []byte
+	Elapsed     time.Duration
+	RequestSize int
+}
+
+// executeRequest sends a (variable-resolved) request and reads the whole response.
+// jar, when not nil, supplies and stores cookies.
+func executeRequest(r APIRequest, jar http.CookieJar) (*exchangeResult, error) {
+	req, reqSize, err := buildHTTPRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := doWithRetries(newHTTPClient(r, jar), req, r.Retries, func() (*http.Request, error) {
+		retry, _, err := buildHTTPRequest(r)
+		return retry, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &exchangeResult{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		Header:      resp.Header,
+		Body:        body,
+		Elapsed:     time.Since(start),
+		RequestSize: reqSize,
+	}, nil
+}
+
+// Most retries a request can ask for, and the pause before the first; each later one waits longer
+const (
+	maxRetries = 5
+	retryDelay = 500 * time.Millisecond
+)
+
+// retryable reports whether an attempt failed in a way that may pass on a second try:
+// the request couldn't be sent, or a gateway or overloaded server turned it away
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// doWithRetries sends req, and up to retries more times while the attempts fail.
+// A body can only be sent once, so rebuild makes the request for each retry.
+// The last attempt's response or error is returned.
+func doWithRetries(client *http.Client, req *http.Request, retries int, rebuild func() (*http.Request, error)) (*http.Response, error) {
+	resp, err := client.Do(req)
+	for attempt := 1; attempt <= min(retries, maxRetries) && retryable(resp, err); attempt++ {
+		if req.Context().Err() != nil {
+			break
+		}
+		select {
+		case <-req.Context().Done():
+			return resp, err
+		case <-time.After(time.Duration(attempt) * retryDelay):
+		}
+		next, buildErr := rebuild()
+		if buildErr != nil {
+			break
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		req = next
+		resp, err = client.Do(req)
+	}
+	return resp, err
+}
+
+// Requests with the same TLS and proxy settings share a transport, so their idle
+// connections are reused instead of piling up in a transport per request
+type transportKey struct {
+	insecure bool
+	proxy    string
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[transportKey]*http.Transport{}
+)
+
+// sharedTransport returns the transport for the request's TLS and proxy settings
+func sharedTransport(r APIRequest) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	key := transportKey{insecure: r.InsecureSkipVerify, proxy: r.Proxy}
+	if transport, ok := transports[key]; ok {
+		return transport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if r.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	switch r.Proxy {
+	case "":
+		// The cloned default transport already honours HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	case proxyDirect:
+		transport.Proxy = nil
+	default:
+		if u, err := url.Parse(r.Proxy); err == nil {
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+	transports[key] = transport
+	return transport
+}
+
+// newHTTPClient builds a client honouring the request's timeout, redirect, TLS and proxy settings,
+// with jar (which may be nil) for cookies. The proxy URL is validated by buildHTTPRequest.
+func newHTTPClient(r APIRequest, jar http.CookieJar) *http.Client {
+	client := &http.Client{Transport: sharedTransport(r), Jar: jar}
+	if r.TimeoutSeconds > 0 {
+		client.Timeout = time.Duration(r.TimeoutSeconds) * time.Second
+	}
+	if !r.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// parseStatusCodes reads a comma/space separated list of status codes, skipping anything invalid
+func parseStatusCodes(text string) []int {
+	codes := []int{}
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if code, err := strconv.Atoi(field); err == nil && code >= 100 && code <= 599 {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return codes
+}
+
+func formatStatusCodes(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, c := range codes {
+		parts[i] = strconv.Itoa(c)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func statusExpected(code int, expected []int) bool {
+	for _, e := range expected {
+		if e == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCustomSettings reports whether any per-request setting differs from its default
+func hasCustomSettings(r APIRequest) bool {
+	return r.Host != "" || r.EnvironmentOverride != "" || r.GzipBody ||
+		r.TimeoutSeconds > 0 || r.Retries > 0 || !r.FollowRedirects || r.InsecureSkipVerify || r.Proxy != "" ||
+		r.ConditionalRequests || r.AcceptLanguage != "" || len(r.ExpectedStatus) > 0
+}
+
+// Proxy setting that connects directly, ignoring the proxy environment variables
+const proxyDirect = "direct"
+
+func validProxyURL(proxy string) bool {
+	u, err := url.Parse(proxy)
+	return err == nil && u.Host != ""
+}
+
+// withConnectionDefaults applies the app-wide proxy, when the request has none of its own,
+// and the app-wide choice to skip TLS verification. An empty proxy leaves the environment variables in charge.
+func withConnectionDefaults(r APIRequest, proxy string, insecure bool) APIRequest {
+	if r.Proxy == "" {
+		r.Proxy = proxy
+	}
+	if insecure {
+		r.InsecureSkipVerify = true
+	}
+	return r
+}
+
+// withDefaultTimeout gives a request without a timeout of its own the default one
+func withDefaultTimeout(r APIRequest, seconds int) APIRequest {
+	if r.TimeoutSeconds <= 0 {
+		r.TimeoutSeconds = seconds
+	}
+	return r
+}
+
+// withGlobalHeaders returns a copy of r with the global headers added,
+// except those the request sets itself (compared case-insensitively)
+func withGlobalHeaders(r APIRequest, global http.Header) APIRequest {
+	if len(global) == 0 {
+		return r
+	}
+	own := map[string]bool{}
+	for k := range r.Headers {
+		own[http.CanonicalHeaderKey(k)] = true
+	}
+	// The request's own auth and language beat global headers
+	if hasAuth(r) {
+		own["Authorization"] = true
+	}
+	if r.AcceptLanguage != "" {
+		own["Accept-Language"] = true
+	}
+	merged := map[string]string{}
+	for k, v := range r.Headers {
+		merged[k] = v
+	}
+	for k, v := range global {
+		if !own[http.CanonicalHeaderKey(k)] {
+			merged[k] = joinHeaderValues(v)
+		}
+	}
+	r.Headers = merged
+	return r
+}
+
+// resolveRequest returns a copy of r with {{var}} references substituted
+func resolveRequest(r APIRequest, vars map[string]string) APIRequest {
+	resolved := r
+	resolved.URL = substituteVariables(r.URL, vars)
+	resolved.Host = substituteVariables(r.Host, vars)
+	resolved.Proxy = substituteVariables(r.Proxy, vars)
+	resolved.AcceptLanguage = substituteVariables(r.AcceptLanguage, vars)
+	if r.Auth != nil {
+		auth := *r.Auth
+		auth.Token = substituteVariables(auth.Token, vars)
+		auth.Username = substituteVariables(auth.Username, vars)
+		auth.Password = substituteVariables(auth.Password, vars)
+		resolved.Auth = &auth
+	}
+	resolved.Body = substituteJSONBody(r.Body, vars)
+	if r.BodyMode == bodyModeNDJSON {
+		// Each line is its own JSON document, so typed variables are resolved line by line
+		lines := strings.Split(r.Body, "\n")
+		for i, line := range lines {
+			lines[i] = substituteJSONBody(line, vars)
+		}
+		resolved.Body = strings.Join(lines, "\n")
+	}
+	resolved.BodyFile = substituteVariables(r.BodyFile, vars)
+	resolved.FormFields = nil
+	for _, f := range r.FormFields {
+		f.Key, f.Value = substituteVariables(f.Key, vars), substituteVariables(f.Value, vars)
+		resolved.FormFields = append(resolved.FormFields, f)
+	}
+	resolved.Headers = map[string]string{}
+	for k, v := range r.Headers {
+		resolved.Headers[substituteVariables(k, vars)] = substituteVariables(v, vars)
+	}
+	return resolved
+}
+
+// headersToText formats a header map for the raw headers editor
+func headersToText(headers map[string]string) string {
+	text := ""
+	for k, v := range headers {
+		for _, value := range headerValues(v) {
+			text += k + ": " + value + "\n"
+		}
+	}
+	return text
+}
+
+// A header given more than once, like Cookie, keeps one entry with its values on
+// separate lines; a header value can't contain a line break, so nothing else is split.
+// Merging them with commas would change the meaning of such headers.
+const headerValueSeparator = "\n"
+
+func joinHeaderValues(values []string) string {
+	return strings.Join(values, headerValueSeparator)
+}
+
+// headerValues splits a stored header into the values sent for it
+func headerValues(v string) []string {
+	return strings.Split(v, headerValueSeparator)
+}
+
+// isTimeoutError reports whether err comes from a deadline, including the client timeout firing mid-body
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// errorHint translates common transport failures into a short troubleshooting hint.
+// It returns "" when the error is not one it recognises.
+func errorHint(err error) string {
+	var dnsErr *net.DNSError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var verifyErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &dnsErr) && (dnsErr.IsNotFound || strings.Contains(dnsErr.Err, "no such host")):
+		return "Host not found. Check the URL and your DNS/network connection."
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "Connection refused. Is the server running and listening on that port?"
+	case errors.Is(err, syscall.ECONNRESET):
+		return "Connection reset by the server. It may have crashed or rejected the request."
+	case isTimeoutError(err):
+		return "The request timed out. The server is slow or unreachable; try a longer timeout in Settings."
+	case errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidCert) || errors.As(err, &verifyErr):
+		return "Certificate error. If the server uses a self-signed certificate, enable \"Skip TLS certificate verification\" in Settings."
+	}
+	return ""
+}
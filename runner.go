@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Collection runner: sends every request of a collection in order and records
+// the outcome of each, for a pass/fail overview of the whole collection.
+
+type runResult struct {
+	Name       string
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Elapsed    time.Duration
+	Size       int
+	Err        error
+	// Outcome of the request's assertions
+	Tests []assertionResult
+	// Problems storing the request's extracted values; they don't fail the request
+	Problems []string
+	// The request is disabled and was not sent
+	Skipped bool
+}
+
+// failed reports whether the request could not be sent, got a 4xx/5xx answer
+// or failed one of its assertions
+func (r runResult) failed() bool {
+	if r.Skipped {
+		return false
+	}
+	return r.Err != nil || r.StatusCode >= 400 || !assertionsPassed(r.Tests)
+}
+
+// statusText is the status column of the results table
+func (r runResult) statusText() string {
+	if r.Skipped {
+		return "Skipped (disabled)"
+	}
+	if r.Err != nil {
+		return "Error: " + r.Err.Error()
+	}
+	if len(r.Tests) == 0 {
+		return r.Status
+	}
+	passed := 0
+	for _, t := range r.Tests {
+		if t.Passed {
+			passed++
+		}
+	}
+	return fmt.Sprintf("%s, %d/%d tests passed", r.Status, passed, len(r.Tests))
+}
+
+type runOptions struct {
+	// Pause between one request finishing and the next being sent
+	Delay       time.Duration
+	StopOnError bool
+	// Cookies shared by the requests of the run; may be nil
+	Jar http.CookieJar
+}
+
+// runCollection sends the requests one by one, calling onResult after each. resolve
+// prepares a request just before it is sent, so values extracted from one response
+// can be used by the next; extract stores those values and returns its problems.
+// Disabled requests are reported as skipped without being sent.
+// The run ends early when stop is closed or, with StopOnError, after the first failure.
+// It returns the number of requests it got through, skipped ones included.
+func runCollection(requests []APIRequest, opts runOptions, resolve func(APIRequest) APIRequest,
+	extract func(APIRequest, *exchangeResult) []string, onResult func(i int, res runResult), stop <-chan struct{}) int {
+	sent := false
+	for i, r := range requests {
+		if !r.Enabled {
+			onResult(i, runResult{Name: r.Name, Method: r.Method, URL: r.URL, Skipped: true})
+			continue
+		}
+		if sent && opts.Delay > 0 {
+			select {
+			case <-stop:
+				return i
+			case <-time.After(opts.Delay):
+			}
+		}
+		select {
+		case <-stop:
+			return i
+		default:
+		}
+		sent = true
+		r = resolve(r)
+		result := runResult{Name: r.Name, Method: r.Method, URL: r.URL}
+		res, err := executeRequest(r, opts.Jar)
+		if err != nil {
+			result.Err = err
+		} else {
+			result.StatusCode, result.Status = res.StatusCode, res.Status
+			result.Elapsed, result.Size = res.Elapsed, len(res.Body)
+			result.Tests = evaluateAssertions(r.Tests, res.StatusCode, res.Elapsed, res.Body)
+			result.Problems = extract(r, res)
+		}
+		onResult(i, result)
+		if opts.StopOnError && result.failed() {
+			return i + 1
+		}
+	}
+	return len(requests)
+}
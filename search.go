@@ -0,0 +1,128 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Find-in-response. The response box shows matches wrapped in markers, so the
+// text it displays is never the response itself; responseSearch keeps the pristine
+// text, and copy, JSONPath and saving read from here rather than from the box.
+
+const (
+	currentMatchOpen  = "【"
+	currentMatchClose = "】"
+	otherMatchOpen    = "〔"
+	otherMatchClose   = "〕"
+)
+
+type searchOptions struct {
+	Regex         bool // The query is a Go regular expression
+	CaseSensitive bool
+}
+
+// searchPattern compiles a query; a literal query is quoted so it matches as typed
+func searchPattern(query string, opts searchOptions) (*regexp.Regexp, error) {
+	expr := query
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(query)
+	} else if _, err := regexp.Compile(query); err != nil {
+		// Report the error against the pattern as typed, without the flags added below
+		return nil, err
+	}
+	if !opts.CaseSensitive {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+type responseSearch struct {
+	text    string // Text shown in the response box, without markers
+	query   string // Query the matches were found for
+	options searchOptions
+	matches [][2]int // Byte ranges of the matches in text
+	current int      // Index of the current match, -1 when there is none
+}
+
+func newResponseSearch() *responseSearch {
+	return &responseSearch{current: -1}
+}
+
+// setText replaces the searched text, dropping any previous search
+func (s *responseSearch) setText(text string) {
+	s.text = text
+	s.clear()
+}
+
+func (s *responseSearch) clear() {
+	s.query = ""
+	s.options = searchOptions{}
+	s.matches = nil
+	s.current = -1
+}
+
+// find looks for query and selects the first match, returning the number of matches.
+// A query that doesn't compile leaves the previous search as it was.
+func (s *responseSearch) find(query string, opts searchOptions) (int, error) {
+	if query == "" {
+		s.clear()
+		return 0, nil
+	}
+	pattern, err := searchPattern(query, opts)
+	if err != nil {
+		return 0, err
+	}
+	s.clear()
+	s.query, s.options = query, opts
+	for _, loc := range pattern.FindAllStringIndex(s.text, -1) {
+		// Empty matches, as from "a*", have nothing to highlight
+		if loc[0] < loc[1] {
+			s.matches = append(s.matches, [2]int{loc[0], loc[1]})
+		}
+	}
+	if len(s.matches) > 0 {
+		s.current = 0
+	}
+	return len(s.matches), nil
+}
+
+// searched reports whether the current matches are for query with opts
+func (s *responseSearch) searched(query string, opts searchOptions) bool {
+	return s.query == query && s.options == opts
+}
+
+// move selects the match delta places from the current one, wrapping around
+func (s *responseSearch) move(delta int) {
+	if len(s.matches) == 0 {
+		return
+	}
+	s.current = ((s.current+delta)%len(s.matches) + len(s.matches)) % len(s.matches)
+}
+
+// highlighted returns the text for display, with the current match in 【】 and the others in 〔〕
+func (s *responseSearch) highlighted() string {
+	if len(s.matches) == 0 {
+		return s.text
+	}
+	var sb strings.Builder
+	last := 0
+	for i, m := range s.matches {
+		open, close := otherMatchOpen, otherMatchClose
+		if i == s.current {
+			open, close = currentMatchOpen, currentMatchClose
+		}
+		sb.WriteString(s.text[last:m[0]])
+		sb.WriteString(open + s.text[m[0]:m[1]] + close)
+		last = m[1]
+	}
+	sb.WriteString(s.text[last:])
+	return sb.String()
+}
+
+// currentRow returns the line of the current match, counting from 0
+func (s *responseSearch) currentRow() int {
+	if s.current < 0 || s.current >= len(s.matches) {
+		return 0
+	}
+	return strings.Count(s.text[:s.matches[s.current][0]], "\n")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const searchSample = `{"name": "Ada", "role": "admin", "team": "Admins"}`
+
+// The markers must only ever reach the displayed text, never the text that copy,
+// JSONPath and saving read
+func assertNoMarkers(t *testing.T, s *responseSearch) {
+	t.Helper()
+	for _, marker := range []string{currentMatchOpen, currentMatchClose, otherMatchOpen, otherMatchClose} {
+		if strings.Contains(s.text, marker) {
+			t.Errorf("text contains marker %q: %s", marker, s.text)
+		}
+	}
+}
+
+func TestResponseSearchLiteral(t *testing.T) {
+	s := newResponseSearch()
+	s.setText(searchSample)
+	n, err := s.find("admin", searchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("got %d matches, want 2", n)
+	}
+	want := `{"name": "Ada", "role": "【admin】", "team": "〔Admin〕s"}`
+	if got := s.highlighted(); got != want {
+		t.Errorf("highlighted() = %s, want %s", got, want)
+	}
+	assertNoMarkers(t, s)
+
+	s.move(1)
+	want = `{"name": "Ada", "role": "〔admin〕", "team": "【Admin】s"}`
+	if got := s.highlighted(); got != want {
+		t.Errorf("after move, highlighted() = %s, want %s", got, want)
+	}
+	assertNoMarkers(t, s)
+	if s.text != searchSample {
+		t.Errorf("text changed to %s", s.text)
+	}
+}
+
+func TestResponseSearchLiteralQuotesPattern(t *testing.T) {
+	s := newResponseSearch()
+	s.setText("a.b axb")
+	n, err := s.find("a.b", searchOptions{CaseSensitive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("got %d matches, want 1", n)
+	}
+	if got, want := s.highlighted(), "【a.b】 axb"; got != want {
+		t.Errorf("highlighted() = %s, want %s", got, want)
+	}
+	assertNoMarkers(t, s)
+}
+
+func TestResponseSearchRegex(t *testing.T) {
+	s := newResponseSearch()
+	s.setText(searchSample)
+	n, err := s.find(`"[A-Z]\w+"`, searchOptions{Regex: true, CaseSensitive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("got %d matches, want 2", n)
+	}
+	got := s.highlighted()
+	for _, part := range []string{`【"Ada"】`, `〔"Admins"〕`} {
+		if !strings.Contains(got, part) {
+			t.Errorf("highlighted() = %s, want it to contain %s", got, part)
+		}
+	}
+	assertNoMarkers(t, s)
+
+	// A pattern that doesn't compile keeps the previous matches
+	if _, err := s.find("(", searchOptions{Regex: true}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if s.highlighted() != got {
+		t.Errorf("invalid pattern changed the matches: %s", s.highlighted())
+	}
+	assertNoMarkers(t, s)
+}
+
+func TestResponseSearchSetTextClears(t *testing.T) {
+	s := newResponseSearch()
+	s.setText(searchSample)
+	if _, err := s.find("Ada", searchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	s.setText("Ada again")
+	if got := s.highlighted(); got != "Ada again" {
+		t.Errorf("highlighted() after setText = %s", got)
+	}
+	assertNoMarkers(t, s)
+}
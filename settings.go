@@ -0,0 +1,81 @@
+package main
+
+import "time"
+
+// Preference keys for app-wide settings (stored via fyne Preferences)
+
+const appID = "com.codealchemy.postman"
+
+const (
+	prefAutoSave              = "autoSave"
+	prefLiveSearch            = "liveSearch"
+	prefSearchRegex           = "searchRegex"
+	prefSearchCaseSensitive   = "searchCaseSensitive"
+	prefSearchPinned          = "searchPinned"
+	prefJSONPathAutoApply     = "jsonPathAutoApply"
+	prefSlowResponseMs        = "slowResponseMs"
+	prefLargeResponseKB       = "largeResponseKB"
+	prefRecentHeaders         = "recentHeaders"
+	prefShowStatusLegend      = "showStatusLegend"
+	prefGlobalHeaders         = "globalHeaders"
+	prefMaxResponseMB         = "maxResponseMB"
+	prefDefaultResponseTab    = "defaultResponseTab"
+	prefGlobalVariables       = "globalVariables"
+	prefVariablePrecedence    = "variablePrecedence"
+	prefCaptureRawHeaders     = "captureRawHeaders"
+	prefPrettyAnyContentType  = "prettyAnyContentType"
+	prefKeepScratch           = "keepScratch"
+	prefScratchVariables      = "scratchVariables"
+	prefDefaultTimeoutSeconds = "defaultTimeoutSeconds"
+	prefNamingTemplate        = "namingTemplate"
+	prefPersistCookies        = "persistCookies"
+	prefProxyMode             = "proxyMode"
+	prefProxyURL              = "proxyURL"
+	prefInsecureSkipVerify    = "insecureSkipVerify"
+	prefParsedURL             = "parsedURL"
+)
+
+// Choices for the app-wide proxy
+const (
+	proxyModeSystem = "System (HTTP_PROXY / HTTPS_PROXY)"
+	proxyModeNone   = "No proxy"
+	proxyModeCustom = "Custom"
+)
+
+// Response time/size thresholds used to flag slow or large responses
+const (
+	defaultSlowResponseMs  = 1000
+	defaultLargeResponseKB = 1024
+)
+
+// Timeout for requests that don't set one
+const defaultTimeoutSeconds = 30
+
+// Response bodies beyond this are truncated so a huge download can't exhaust memory
+const defaultMaxResponseMB = 50
+
+// Default response tab option meaning "stay on whichever tab is open"
+const keepCurrentTab = "(Keep current tab)"
+
+const (
+	thresholdOK = iota
+	thresholdExceeded
+	thresholdFarExceeded
+)
+
+// thresholdLevel grades a value: over the limit is "exceeded", over double the limit is "far exceeded"
+func thresholdLevel(value, limit float64) int {
+	switch {
+	case limit <= 0 || value <= limit:
+		return thresholdOK
+	case value <= 2*limit:
+		return thresholdExceeded
+	}
+	return thresholdFarExceeded
+}
+
+// Delay before an auto-save is written, so bursts of edits produce one write
+const autoSaveDelay = 800 * time.Millisecond
+
+// Delay after the last keystroke before a live search runs
+const liveSearchDelay = 250 * time.Millisecond
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Share links: a request as one copyable string for pasting into chat,
+//
+//	codealchemy://request?v=1&data=<base64url of the gzipped request JSON>
+
+const (
+	shareLinkScheme  = "codealchemy"
+	shareLinkVersion = "1"
+	// Decoded requests larger than this are rejected
+	maxShareLinkBytes = 1 << 20
+)
+
+// encodeShareLink leaves out credentials, since links end up in chat logs: the Auth
+// token, username and password, the Authorization, Proxy-Authorization and Cookie
+// headers, and the user info of the proxy URL. The auth type is kept so the receiver
+// sees what to fill in. Secrets in custom headers such as X-API-Key are not detected.
+func encodeShareLink(r APIRequest) (string, error) {
+	data, err := json.Marshal(withoutCredentials(r))
+	if err != nil {
+		return "", err
+	}
+	compressed, err := gzipBytes(data)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("v", shareLinkVersion)
+	query.Set("data", base64.RawURLEncoding.EncodeToString(compressed))
+	return shareLinkScheme + "://request?" + query.Encode(), nil
+}
+
+// Headers dropped from share links, compared case-insensitively
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+func withoutCredentials(r APIRequest) APIRequest {
+	if r.Auth != nil {
+		r.Auth = &RequestAuth{Type: r.Auth.Type}
+	}
+	headers := map[string]string{}
+	for k, v := range r.Headers {
+		if !isCredentialHeader(k) {
+			headers[k] = v
+		}
+	}
+	r.Headers = headers
+	if u, err := url.Parse(r.Proxy); err == nil && u.User != nil {
+		u.User = nil
+		r.Proxy = u.String()
+	}
+	return r
+}
+
+func isCredentialHeader(name string) bool {
+	for _, h := range credentialHeaders {
+		if strings.EqualFold(name, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeShareLink parses a share link and checks that it holds a usable request
+func decodeShareLink(link string) (APIRequest, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return APIRequest{}, fmt.Errorf("not a share link: %v", err)
+	}
+	if u.Scheme != shareLinkScheme || u.Host != "request" {
+		return APIRequest{}, fmt.Errorf("not a share link: expected %s://request?...", shareLinkScheme)
+	}
+	if v := u.Query().Get("v"); v != shareLinkVersion {
+		return APIRequest{}, fmt.Errorf("unsupported share link version %q", v)
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(u.Query().Get("data"))
+	if err != nil {
+		return APIRequest{}, fmt.Errorf("share link data is corrupted: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return APIRequest{}, fmt.Errorf("share link data is corrupted: %v", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(zr, maxShareLinkBytes+1))
+	if err != nil {
+		return APIRequest{}, fmt.Errorf("share link data is corrupted: %v", err)
+	}
+	if len(data) > maxShareLinkBytes {
+		return APIRequest{}, fmt.Errorf("share link is too large")
+	}
+	var r APIRequest
+	if err := json.Unmarshal(data, &r); err != nil {
+		return APIRequest{}, fmt.Errorf("share link does not contain a request: %v", err)
+	}
+	if r.Method == "" || r.URL == "" {
+		return APIRequest{}, fmt.Errorf("share link request has no method or URL")
+	}
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+	return r, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Local storage helpers
+//
+// The workspace file holds workspaces, environments, flows and the collection index.
+// Each collection's requests are stored in a separate file under the collections
+// directory and read on first use, so large collections don't slow down startup.
+// The file is a list of requests, or an object with the requests and folders
+// once the collection has folders.
+
+func getStoragePath() string {
+	dir, _ := os.UserHomeDir()
+	return filepath.Join(dir, ".postman-go-workspaces.json")
+}
+
+func getCollectionsDir() string {
+	dir, _ := os.UserHomeDir()
+	return filepath.Join(dir, ".postman-go-collections")
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// newRequestsFileName returns a unique file name for a collection's requests
+func newRequestsFileName(collection string) string {
+	slug := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(collection), "-"), "-")
+	if len(slug) > 40 {
+		slug = slug[:40]
+	}
+	return fmt.Sprintf("%d-%s.json", time.Now().UnixNano(), slug)
+}
+
+// requestsFile is the contents of a collection's requests file when it has folders
+type requestsFile struct {
+	Requests []APIRequest `json:"requests"`
+	Folders  []Folder     `json:"folders,omitempty"`
+}
+
+// isLoaded reports whether the collection's requests are in memory
+func (c *Collection) isLoaded() bool {
+	return c.loaded || c.RequestsFile == ""
+}
+
+// load reads the collection's requests from its file if that hasn't happened yet
+func (c *Collection) load() error {
+	if c.isLoaded() {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(getCollectionsDir(), c.RequestsFile))
+	if err != nil {
+		return fmt.Errorf("loading collection %q: %v", c.Name, err)
+	}
+	var file requestsFile
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &file.Requests)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return fmt.Errorf("loading collection %q: %v", c.Name, err)
+	}
+	c.Requests = file.Requests
+	c.Folders = file.Folders
+	c.loaded = true
+	return nil
+}
+
+// removeFile deletes the collection's requests file, once the collection itself is gone
+func (c *Collection) removeFile() error {
+	if c.RequestsFile == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(getCollectionsDir(), c.RequestsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// loadCollections loads every collection of the workspace, for workspace-wide views like flows
+func (ws *Workspace) loadCollections() error {
+	for i := range ws.Collections {
+		if err := ws.Collections[i].load(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadWorkspaces() ([]Workspace, error) {
+	path := getStoragePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return []Workspace{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var workspaces []Workspace
+	err = json.Unmarshal(data, &workspaces)
+	return workspaces, err
+}
+
+// Saving is split in two so the model can be captured where it is edited and
+// written out from another goroutine.
+
+// workspacesSnapshot is the encoded contents of the workspace file and the request
+// files of loaded collections. Collections that were never loaded are unchanged and
+// keep their existing files.
+type workspacesSnapshot struct {
+	index []byte
+	// Contents of the request files of loaded collections, by file name
+	requestFiles map[string][]byte
+}
+
+// snapshotWorkspaces encodes the workspaces; it assigns a request file to loaded
+// collections that don't have one yet
+func snapshotWorkspaces(workspaces []Workspace) (*workspacesSnapshot, error) {
+	snapshot := &workspacesSnapshot{requestFiles: map[string][]byte{}}
+	index := make([]Workspace, len(workspaces))
+	for i := range workspaces {
+		index[i] = workspaces[i]
+		index[i].Collections = make([]Collection, len(workspaces[i].Collections))
+		for j := range workspaces[i].Collections {
+			col := &workspaces[i].Collections[j]
+			if col.isLoaded() {
+				if col.RequestsFile == "" {
+					col.RequestsFile = newRequestsFileName(col.Name)
+				}
+				requests := col.Requests
+				if requests == nil {
+					requests = []APIRequest{}
+				}
+				var contents interface{} = requests
+				if len(col.Folders) > 0 {
+					contents = requestsFile{Requests: requests, Folders: col.Folders}
+				}
+				data, err := json.MarshalIndent(contents, "", "  ")
+				if err != nil {
+					return nil, err
+				}
+				snapshot.requestFiles[col.RequestsFile] = data
+				col.loaded = true
+			}
+			index[i].Collections[j] = *col
+			index[i].Collections[j].Requests = nil
+			index[i].Collections[j].Folders = nil
+		}
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	snapshot.index = data
+	return snapshot, nil
+}
+
+// write stores the request files, then the workspace file that refers to them
+func (s *workspacesSnapshot) write() error {
+	if err := os.MkdirAll(getCollectionsDir(), 0755); err != nil {
+		return err
+	}
+	for name, data := range s.requestFiles {
+		if err := os.WriteFile(filepath.Join(getCollectionsDir(), name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(getStoragePath(), s.index, 0644)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Table view for responses that are JSON arrays of objects
+
+type tableData struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// cellText renders a JSON value for a table cell; strings are shown unquoted
+func cellText(raw json.RawMessage) string {
+	var s string
+	if len(raw) > 0 && raw[0] == '"' && json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
+
+// decodeOrderedObject reads one JSON object, keeping its keys in document order
+func decodeOrderedObject(dec *json.Decoder) ([]string, map[string]json.RawMessage, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("element is not an object")
+	}
+	keys := []string{}
+	values := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, seen := values[key]; !seen {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	return keys, values, nil
+}
+
+// tabularJSON converts a non-empty JSON array of objects into rows with a column per key.
+// Columns follow first appearance; ok is false when the body does not have that shape.
+func tabularJSON(body []byte) (tableData, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return tableData{}, false
+	}
+	var objects []map[string]json.RawMessage
+	columns := []string{}
+	known := map[string]bool{}
+	for dec.More() {
+		keys, values, err := decodeOrderedObject(dec)
+		if err != nil {
+			return tableData{}, false
+		}
+		for _, k := range keys {
+			if !known[k] {
+				known[k] = true
+				columns = append(columns, k)
+			}
+		}
+		objects = append(objects, values)
+	}
+	if len(objects) == 0 || len(columns) == 0 {
+		return tableData{}, false
+	}
+	data := tableData{Columns: columns}
+	for _, obj := range objects {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			if raw, ok := obj[col]; ok {
+				row[i] = cellText(raw)
+			}
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	return data, true
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Request tabs. There is one request form and one response area; each tab keeps
+// a copy of what they showed when it was last active and puts it back when it is
+// selected again.
+
+// responseSnapshot is the response area of a tab that isn't active
+type responseSnapshot struct {
+	Body               []byte
+	ContentType        string
+	ContentDisposition string
+	URL                string
+	StatusCode         int
+	Elapsed            time.Duration
+	// Text of the response box, without search highlighting
+	Text        string
+	Headers     string
+	RawHeaders  string
+	Status      string
+	StatusFill  color.Color
+	Meta        string
+	MetaColor   color.Color
+	Banner      string // Empty when the truncation banner is hidden
+	Extracted   string
+	JSONPath    string
+	Interaction *interaction
+}
+
+type requestTab struct {
+	// Saved request the tab was opened from; Request is -1 for an unsaved request
+	Workspace  string
+	Collection int
+	Request    int
+	Title      string
+	// Form contents when the tab was last left
+	Draft APIRequest
+	// Form contents when the request was opened or last saved, to spot unsaved changes
+	Baseline APIRequest
+	Response responseSnapshot
+}
+
+// isSaved reports whether the tab shows the saved request at collection/request of workspace
+func (t *requestTab) isSaved(workspace string, collection, request int) bool {
+	return t.Request >= 0 && t.Workspace == workspace && t.Collection == collection && t.Request == request
+}
+
+// sameRequest compares two requests field by field, through their JSON encoding
+func sameRequest(a, b APIRequest) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// tabButton is a tab in the tab bar; a middle click closes it
+type tabButton struct {
+	widget.Button
+	onClose func()
+}
+
+func newTabButton(label string, onSelect, onClose func()) *tabButton {
+	b := &tabButton{onClose: onClose}
+	b.Text = label
+	b.OnTapped = onSelect
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *tabButton) MouseDown(ev *desktop.MouseEvent) {
+	if ev.Button == desktop.MouseButtonTertiary && b.onClose != nil {
+		b.onClose()
+	}
+}
+
+func (b *tabButton) MouseUp(*desktop.MouseEvent) {}
+
+var _ desktop.Mouseable = (*tabButton)(nil)
+var _ fyne.Tappable = (*tabButton)(nil)
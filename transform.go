@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// Reshaping a response into a new object, one mapping per line:
+//
+//	id = $.data.user.id
+//	email = $.data.user.contact.email
+//	firstItem = $.items[0]
+//
+// Each JSONPath is evaluated against the response and stored under the target name.
+// Fields keep the order of the mapping lines.
+
+// applyTransform evaluates the mapping against a JSON body and returns the indented
+// result, plus one message per line that could not be parsed or evaluated.
+func applyTransform(lines []string, body []byte) (string, []string) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", []string{"response is not valid JSON"}
+	}
+	var out bytes.Buffer
+	problems := []string{}
+	out.WriteString("{")
+	fields := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			problems = append(problems, fmt.Sprintf("%s: expected target = $.path", line))
+			continue
+		}
+		target, path := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		value, err := jsonpath.Get(path, data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", target, err))
+			value = nil
+		}
+		key, _ := json.Marshal(target)
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", target, err))
+			encoded = []byte("null")
+		}
+		if fields > 0 {
+			out.WriteString(",")
+		}
+		out.Write(key)
+		out.WriteString(":")
+		out.Write(encoded)
+		fields++
+	}
+	out.WriteString("}")
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, out.Bytes(), "", "    "); err != nil {
+		return out.String(), problems
+	}
+	return pretty.String(), problems
+}